@@ -2,23 +2,31 @@ package main
 
 import (
 	"context"
+	"errors"
 	"github.com/ilam072/wbtech-l0/backend/internal/broker/kafka/consumer"
+	"github.com/ilam072/wbtech-l0/backend/internal/broker/kafka/dlq"
 	"github.com/ilam072/wbtech-l0/backend/internal/broker/kafka/handler"
+	"github.com/ilam072/wbtech-l0/backend/internal/broker/kafka/outbox"
 	"github.com/ilam072/wbtech-l0/backend/internal/cache"
 	"github.com/ilam072/wbtech-l0/backend/internal/config"
 	"github.com/ilam072/wbtech-l0/backend/internal/converter"
+	"github.com/ilam072/wbtech-l0/backend/internal/health"
+	"github.com/ilam072/wbtech-l0/backend/internal/metrics"
 	"github.com/ilam072/wbtech-l0/backend/internal/repo/postgres"
 	"github.com/ilam072/wbtech-l0/backend/internal/rest"
 	"github.com/ilam072/wbtech-l0/backend/internal/service"
+	"github.com/ilam072/wbtech-l0/backend/internal/tracing"
 	"github.com/ilam072/wbtech-l0/backend/internal/validator"
 	"github.com/ilam072/wbtech-l0/backend/pkg/db"
 	"github.com/ilam072/wbtech-l0/backend/pkg/logger/handlers/slogpretty"
 	"github.com/ilam072/wbtech-l0/backend/pkg/logger/sl"
+	"github.com/ilam072/wbtech-l0/backend/pkg/retry"
 	"log"
 	"log/slog"
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 )
 
 // @title Order Service
@@ -36,10 +44,15 @@ func main() {
 
 	l := initLogger()
 
-	orderValidator := validator.New()
+	shutdownTracing, err := tracing.Setup(cfg.TracingConfig)
+	if err != nil {
+		panic(err)
+	}
+
+	orderValidator := validator.New(l, cfg.ValidationConfig)
 	orderRepo := postgres.NewOrderRepo(pool)
 	converterr := converter.New()
-	cache := cache.New(orderRepo, converterr)
+	cache := cache.New(orderRepo, converterr, cfg.CacheConfig)
 	orderService := service.NewOrderService(orderRepo, cache, converterr)
 
 	kafkaConsumer := consumer.New(
@@ -48,31 +61,99 @@ func main() {
 		cfg.KafkaConfig.Brokers...,
 	)
 
+	deadLetterProducer := dlq.New(
+		cfg.KafkaConfig.DLQTopic,
+		cfg.KafkaConfig.Brokers...,
+	)
+
+	outboxWriter := outbox.NewWriter(
+		cfg.OutboxConfig.Topic,
+		cfg.KafkaConfig.Brokers...,
+	)
+	outboxRelay := outbox.New(l, orderRepo, outboxWriter, cfg.OutboxConfig)
+
+	metrics.Registry.MustRegister(
+		metrics.NewCacheCollector(cache),
+		metrics.NewPoolCollector(pool),
+	)
+
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
 	sigs := make(chan os.Signal, 1)
 	signal.Notify(sigs, syscall.SIGINT, syscall.SIGTERM)
 
+	retryPolicy := retry.Policy{
+		MaxAttempts:    cfg.KafkaConfig.RetryMaxAttempts,
+		InitialBackoff: cfg.KafkaConfig.RetryInitialBackoff,
+		MaxBackoff:     cfg.KafkaConfig.RetryMaxBackoff,
+		Multiplier:     cfg.KafkaConfig.RetryMultiplier,
+	}
+
 	orderConsumerHandler := handler.NewOrderConsumerHandler(
 		l,
 		kafkaConsumer,
 		orderService,
 		orderValidator,
+		deadLetterProducer,
+		retryPolicy,
+		cfg.KafkaConfig.Workers,
+		cfg.KafkaConfig.DrainTimeout,
 	)
 
+	statusConsumer := consumer.New(
+		cfg.KafkaConfig.StatusTopic,
+		cfg.KafkaConfig.StatusGroupID,
+		cfg.KafkaConfig.Brokers...,
+	)
+
+	statusEventHandler := handler.NewStatusEventHandler(
+		l,
+		statusConsumer,
+		orderService,
+		orderValidator,
+		deadLetterProducer,
+		retryPolicy,
+		cfg.KafkaConfig.DrainTimeout,
+	)
+
+	consumerDone := make(chan error, 1)
 	go func() {
-		if err := orderConsumerHandler.Start(ctx); err != nil {
-			l.Error("failed to start consumer", sl.Err(err))
+		err := orderConsumerHandler.Start(ctx)
+		if err != nil {
+			l.Error("kafka consumer stopped", sl.Err(err))
+			// Nothing is ingested without the consumer: shut down so that
+			// the process is restarted instead of staying up idle.
+			cancel()
 		}
+		consumerDone <- err
 	}()
 
-	err = cache.Preload(context.Background(), cfg.CacheConfig.PreloadLimit)
-	if err != nil {
-		log.Fatalln("error preloading cache", sl.Err(err))
-	}
+	statusDone := make(chan error, 1)
+	go func() {
+		err := statusEventHandler.Start(ctx)
+		if err != nil {
+			l.Error("status event consumer stopped", sl.Err(err))
+			cancel()
+		}
+		statusDone <- err
+	}()
+
+	relayDone := make(chan struct{})
+	go func() {
+		outboxRelay.Start(ctx)
+		close(relayDone)
+	}()
 
-	h := rest.NewHandler(l, orderService)
+	cacheWarm := &health.Flag{}
+	checker := health.New(cfg.ServerConfig.HealthCheckTimeout)
+	checker.Add("postgres", pool.Ping)
+	checker.Add("kafka", kafkaConsumer.Ping)
+	checker.Add("cache", cacheWarm.Check)
+
+	// The server starts before the cache is warmed up so that liveness
+	// probes pass while /readyz keeps traffic away until it is done.
+	h := rest.NewHandler(l, orderService, orderValidator, cache, checker, cfg.ServerConfig.AdminToken)
 	go func() {
 		if err := h.Listen(cfg.ServerConfig.Address()); err != nil {
 			l.Error("failed to start server", sl.Err(err))
@@ -80,19 +161,106 @@ func main() {
 		}
 	}()
 
-	<-sigs
+	warmUpCache(l, cache, cfg.CacheConfig)
+	cacheWarm.Set()
+
+	if cfg.CacheConfig.SnapshotPath != "" && cfg.CacheConfig.SnapshotInterval > 0 {
+		go runCacheSnapshots(ctx, l, cache, cfg.CacheConfig.SnapshotPath, cfg.CacheConfig.SnapshotInterval)
+	}
+
+	select {
+	case <-sigs:
+	case <-ctx.Done():
+	}
 	l.Info("shutting down...")
+
+	exitCode := 0
+
+	// Stop fetching and wait for the fetched messages to be stored and their
+	// offsets committed before anything they depend on is closed.
 	cancel()
+	if err := <-consumerDone; err != nil {
+		exitCode = 1
+	}
+	if err := <-statusDone; err != nil {
+		exitCode = 1
+	}
+	<-relayDone
 
-	if err := h.Shutdown(); err != nil {
+	if err := h.Shutdown(cfg.ServerConfig.ShutdownTimeout); err != nil {
 		l.Error("failed to shutdown server", sl.Err(err))
+		exitCode = 1
 	}
 
 	if err := kafkaConsumer.Close(); err != nil {
 		l.Error("failed to close kafka consumer", sl.Err(err))
 	}
 
-	l.Info("application stopped")
+	if err := statusConsumer.Close(); err != nil {
+		l.Error("failed to close status event consumer", sl.Err(err))
+	}
+
+	if err := deadLetterProducer.Close(); err != nil {
+		l.Error("failed to close dead letter producer", sl.Err(err))
+	}
+
+	if err := outboxWriter.Close(); err != nil {
+		l.Error("failed to close outbox writer", sl.Err(err))
+	}
+
+	if cfg.CacheConfig.SnapshotPath != "" {
+		if err := cache.SaveSnapshot(context.Background(), cfg.CacheConfig.SnapshotPath); err != nil {
+			l.Error("failed to save cache snapshot", sl.Err(err))
+		}
+	}
+
+	if err := shutdownTracing(context.Background()); err != nil {
+		l.Error("failed to flush traces", sl.Err(err))
+	}
+
+	pool.Close()
+
+	l.Info("application stopped", slog.Int("exit_code", exitCode))
+	_ = os.Stdout.Sync()
+
+	if exitCode != 0 {
+		os.Exit(exitCode)
+	}
+}
+
+// warmUpCache restores the cache from its snapshot and falls back to
+// preloading the latest orders when there is no usable snapshot.
+func warmUpCache(l *slog.Logger, c *cache.OrderCache, cfg config.CacheConfig) {
+	if cfg.SnapshotPath != "" {
+		n, err := c.LoadSnapshot(context.Background(), cfg.SnapshotPath)
+		if err == nil {
+			l.Info("cache restored from snapshot", slog.Int("orders", n))
+			return
+		}
+		if !errors.Is(err, os.ErrNotExist) {
+			l.Warn("cache snapshot not used, preloading", sl.Err(err))
+		}
+	}
+
+	if err := c.Preload(context.Background(), cfg.PreloadLimit); err != nil {
+		log.Fatalln("error preloading cache", sl.Err(err))
+	}
+}
+
+func runCacheSnapshots(ctx context.Context, l *slog.Logger, c *cache.OrderCache, path string, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := c.SaveSnapshot(ctx, path); err != nil {
+				l.Error("failed to save cache snapshot", sl.Err(err))
+			}
+		}
+	}
 }
 
 func initLogger() *slog.Logger {
@@ -0,0 +1,206 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"github.com/ilam072/wbtech-l0/backend/internal/broker/kafka/producer"
+	"github.com/ilam072/wbtech-l0/backend/pkg/logger/handlers/slogpretty"
+	"github.com/ilam072/wbtech-l0/backend/pkg/logger/sl"
+	"github.com/segmentio/kafka-go"
+	"log/slog"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+)
+
+const usage = `Usage:
+  orderctl produce [flags] [file or directory ...]
+
+Commands:
+  produce  publish generated orders, or the given JSON files, to Kafka`
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprintln(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	l := initLogger()
+
+	var err error
+	switch os.Args[1] {
+	case "produce":
+		err = produce(l, os.Args[2:])
+	default:
+		fmt.Fprintln(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	if errors.Is(err, flag.ErrHelp) {
+		os.Exit(2)
+	}
+	if err != nil {
+		l.Error("orderctl failed", sl.Err(err))
+		os.Exit(1)
+	}
+}
+
+func produce(l *slog.Logger, args []string) error {
+	fs := flag.NewFlagSet("produce", flag.ContinueOnError)
+	brokers := fs.String("brokers", env("KAFKA_BROKERS", "localhost:9092"), "comma separated Kafka brokers")
+	topic := fs.String("topic", env("KAFKA_TOPIC", "orders"), "topic to publish to")
+	count := fs.Int("count", 100, "number of orders to generate, 0 to run until interrupted")
+	rate := fs.Float64("rate", 10, "messages per second, 0 for no limit")
+	batch := fs.Int("batch", 100, "maximum messages per write")
+	invalid := fs.Float64("invalid", 0, "share of generated orders breaking a validation rule, from 0 to 1")
+	seed := fs.Uint64("seed", 0, "generator seed, 0 for a random one")
+	date := fs.String("date", "", "RFC 3339 time orders are generated before, now by default")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), usage)
+		fmt.Fprintln(fs.Output(), "\nFlags:")
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *invalid < 0 || *invalid > 1 {
+		return errors.New("-invalid must be between 0 and 1")
+	}
+	if *count < 0 || *rate < 0 || *batch < 1 {
+		return errors.New("-count and -rate must not be negative, -batch must be positive")
+	}
+
+	var (
+		next  func() kafka.Message
+		total = *count
+	)
+	if fs.NArg() > 0 {
+		msgs, err := producer.FileMessages(fs.Args()...)
+		if err != nil {
+			return err
+		}
+		if len(msgs) == 0 {
+			return errors.New("no messages found in the given files")
+		}
+		total = len(msgs)
+		next = func() kafka.Message {
+			msg := msgs[0]
+			msgs = msgs[1:]
+			return msg
+		}
+	} else {
+		now := time.Now()
+		if *date != "" {
+			var err error
+			if now, err = time.Parse(time.RFC3339, *date); err != nil {
+				return fmt.Errorf("-date: %w", err)
+			}
+		}
+		if *seed == 0 {
+			*seed = uint64(time.Now().UnixNano())
+		}
+		l.Info("generating orders", slog.Uint64("seed", *seed), slog.String("date", now.Format(time.RFC3339)))
+
+		g := producer.NewGenerator(*seed, *invalid, now)
+		next = func() kafka.Message {
+			order, _ := g.Next()
+			msg, err := producer.OrderMessage(order)
+			if err != nil {
+				// dto.Order always marshals.
+				panic(err)
+			}
+			return msg
+		}
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	p := producer.New(*topic, strings.Split(*brokers, ",")...)
+	defer p.Close()
+
+	start := time.Now()
+	sent, err := publish(ctx, p, total, *rate, *batch, next)
+	l.Info("messages published",
+		slog.String("topic", *topic),
+		slog.Int("sent", sent),
+		slog.Duration("elapsed", time.Since(start).Round(time.Millisecond)),
+	)
+	return err
+}
+
+// publish writes messages returned by next at rate messages per second, in
+// batches of at most batch messages, until total messages are sent or ctx is
+// done. A zero total publishes until ctx is done. When the writes fall behind
+// the rate, the following batches grow to catch up.
+func publish(
+	ctx context.Context,
+	p *producer.Producer,
+	total int,
+	rate float64,
+	batch int,
+	next func() kafka.Message,
+) (int, error) {
+	start := time.Now()
+	sent := 0
+
+	for total == 0 || sent < total {
+		if ctx.Err() != nil {
+			return sent, nil
+		}
+
+		n := batch
+		if rate > 0 {
+			n = int(time.Since(start).Seconds()*rate) + 1 - sent
+			if n <= 0 {
+				due := start.Add(time.Duration(float64(sent) / rate * float64(time.Second)))
+				select {
+				case <-ctx.Done():
+				case <-time.After(time.Until(due)):
+				}
+				continue
+			}
+			n = min(n, batch)
+		}
+		if total > 0 {
+			n = min(n, total-sent)
+		}
+
+		msgs := make([]kafka.Message, n)
+		for i := range msgs {
+			msgs[i] = next()
+		}
+		if err := p.Publish(ctx, msgs...); err != nil {
+			if ctx.Err() != nil {
+				return sent, nil
+			}
+			return sent, err
+		}
+		sent += n
+	}
+	return sent, nil
+}
+
+func env(key, fallback string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return fallback
+}
+
+func initLogger() *slog.Logger {
+	opts := slogpretty.PrettyHandlerOptions{
+		SlogOpts: &slog.HandlerOptions{
+			Level: slog.LevelInfo,
+		},
+	}
+
+	h := opts.NewPrettyHandler(os.Stderr)
+
+	return slog.New(h)
+}
@@ -2,11 +2,13 @@ package consumer
 
 import (
 	"context"
+	"errors"
 	"github.com/segmentio/kafka-go"
 )
 
 type Consumer struct {
-	r *kafka.Reader
+	r       *kafka.Reader
+	brokers []string
 }
 
 func New(topic string, groupId string, addr ...string) *Consumer {
@@ -15,11 +17,28 @@ func New(topic string, groupId string, addr ...string) *Consumer {
 		GroupID: groupId,
 		Topic:   topic,
 	})
-	return &Consumer{r: r}
+	return &Consumer{r: r, brokers: addr}
 }
 
-func (c *Consumer) Consume(ctx context.Context) (kafka.Message, error) {
-	return c.r.ReadMessage(ctx)
+// Fetch returns the next message without committing its offset.
+func (c *Consumer) Fetch(ctx context.Context) (kafka.Message, error) {
+	return c.r.FetchMessage(ctx)
+}
+
+func (c *Consumer) Commit(ctx context.Context, msgs ...kafka.Message) error {
+	return c.r.CommitMessages(ctx, msgs...)
+}
+
+// Ping checks that at least one of the brokers accepts connections.
+func (c *Consumer) Ping(ctx context.Context) error {
+	err := errors.New("no kafka brokers configured")
+	for _, addr := range c.brokers {
+		var conn *kafka.Conn
+		if conn, err = kafka.DialContext(ctx, "tcp", addr); err == nil {
+			return conn.Close()
+		}
+	}
+	return err
 }
 
 func (c *Consumer) Close() error {
@@ -0,0 +1,103 @@
+package dlq
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/ilam072/wbtech-l0/backend/internal/validator"
+	"github.com/ilam072/wbtech-l0/backend/pkg/e"
+	"github.com/segmentio/kafka-go"
+	"strconv"
+	"time"
+)
+
+const (
+	StageDecode   = "decode"
+	StageValidate = "validate"
+	StageCreate   = "create"
+	// StageUpdateStatus is used for status events that could not be applied.
+	StageUpdateStatus = "update_status"
+)
+
+const (
+	HeaderStage             = "x-dlq-stage"
+	HeaderError             = "x-dlq-error"
+	HeaderAttempts          = "x-dlq-attempts"
+	HeaderOriginalTopic     = "x-dlq-original-topic"
+	HeaderOriginalPartition = "x-dlq-original-partition"
+	HeaderOriginalOffset    = "x-dlq-original-offset"
+	HeaderFailedAt          = "x-dlq-failed-at"
+	// HeaderValidationErrors carries a JSON array of validator.FieldError and
+	// is set only for messages rejected by the validator.
+	HeaderValidationErrors = "x-dlq-validation-errors"
+)
+
+// Failure describes why a message was rejected by the consumer pipeline.
+type Failure struct {
+	Stage    string
+	Err      error
+	Attempts int
+}
+
+type Producer struct {
+	w *kafka.Writer
+}
+
+func New(topic string, addr ...string) *Producer {
+	w := &kafka.Writer{
+		Addr:                   kafka.TCP(addr...),
+		Topic:                  topic,
+		Balancer:               &kafka.Hash{},
+		RequiredAcks:           kafka.RequireAll,
+		AllowAutoTopicCreation: true,
+	}
+	return &Producer{w: w}
+}
+
+// Publish republishes the original message to the dead-letter topic keeping
+// its key, value and headers and appending the failure details as headers.
+func (p *Producer) Publish(ctx context.Context, msg kafka.Message, f Failure) error {
+	const op = "dlq.Publish()"
+
+	if err := p.w.WriteMessages(ctx, Message(msg, f, time.Now())); err != nil {
+		return e.Wrap(op, err)
+	}
+	return nil
+}
+
+func (p *Producer) Close() error {
+	return p.w.Close()
+}
+
+// Message builds the dead-letter copy of msg.
+func Message(msg kafka.Message, f Failure, failedAt time.Time) kafka.Message {
+	errText := ""
+	if f.Err != nil {
+		errText = f.Err.Error()
+	}
+
+	headers := make([]kafka.Header, 0, len(msg.Headers)+8)
+	headers = append(headers, msg.Headers...)
+	headers = append(headers,
+		kafka.Header{Key: HeaderStage, Value: []byte(f.Stage)},
+		kafka.Header{Key: HeaderError, Value: []byte(errText)},
+		kafka.Header{Key: HeaderAttempts, Value: []byte(strconv.Itoa(f.Attempts))},
+		kafka.Header{Key: HeaderOriginalTopic, Value: []byte(msg.Topic)},
+		kafka.Header{Key: HeaderOriginalPartition, Value: []byte(strconv.Itoa(msg.Partition))},
+		kafka.Header{Key: HeaderOriginalOffset, Value: []byte(strconv.FormatInt(msg.Offset, 10))},
+		kafka.Header{Key: HeaderFailedAt, Value: []byte(failedAt.UTC().Format(time.RFC3339Nano))},
+	)
+
+	var verr *validator.ValidationError
+	if errors.As(f.Err, &verr) {
+		if fields, err := json.Marshal(verr.Fields); err == nil {
+			headers = append(headers, kafka.Header{Key: HeaderValidationErrors, Value: fields})
+		}
+	}
+
+	return kafka.Message{
+		Key:     msg.Key,
+		Value:   msg.Value,
+		Headers: headers,
+	}
+}
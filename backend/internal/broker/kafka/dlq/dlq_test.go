@@ -0,0 +1,60 @@
+package dlq
+
+import (
+	"errors"
+	"github.com/ilam072/wbtech-l0/backend/internal/validator"
+	"github.com/segmentio/kafka-go"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestMessage(t *testing.T) {
+	original := kafka.Message{
+		Topic:     "orders",
+		Partition: 3,
+		Offset:    128,
+		Key:       []byte("key"),
+		Value:     []byte(`{"order_uid":"broken"`),
+		Headers:   []kafka.Header{{Key: "trace", Value: []byte("abc")}},
+	}
+	failedAt := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	msg := Message(original, Failure{Stage: StageDecode, Err: errors.New("unexpected EOF"), Attempts: 1}, failedAt)
+
+	assert.Equal(t, original.Key, msg.Key)
+	assert.Equal(t, original.Value, msg.Value)
+	assert.Empty(t, msg.Topic)
+
+	headers := make(map[string]string, len(msg.Headers))
+	for _, h := range msg.Headers {
+		headers[h.Key] = string(h.Value)
+	}
+
+	assert.Equal(t, "abc", headers["trace"])
+	assert.Equal(t, StageDecode, headers[HeaderStage])
+	assert.Equal(t, "unexpected EOF", headers[HeaderError])
+	assert.Equal(t, "1", headers[HeaderAttempts])
+	assert.Equal(t, "orders", headers[HeaderOriginalTopic])
+	assert.Equal(t, "3", headers[HeaderOriginalPartition])
+	assert.Equal(t, "128", headers[HeaderOriginalOffset])
+	assert.Equal(t, "2025-01-02T03:04:05Z", headers[HeaderFailedAt])
+	assert.NotContains(t, headers, HeaderValidationErrors)
+}
+
+func TestMessage_ValidationErrors(t *testing.T) {
+	original := kafka.Message{Topic: "orders", Value: []byte(`{}`)}
+	verr := &validator.ValidationError{Fields: []validator.FieldError{
+		{Field: "items[2].sale", Rule: "required", Message: "is required"},
+	}}
+
+	msg := Message(original, Failure{Stage: StageValidate, Err: verr, Attempts: 1}, time.Now())
+
+	var fields string
+	for _, h := range msg.Headers {
+		if h.Key == HeaderValidationErrors {
+			fields = string(h.Value)
+		}
+	}
+	assert.JSONEq(t, `[{"field":"items[2].sale","rule":"required","message":"is required"}]`, fields)
+}
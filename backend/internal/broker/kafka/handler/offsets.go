@@ -0,0 +1,72 @@
+package handler
+
+import (
+	"github.com/segmentio/kafka-go"
+	"sync"
+)
+
+type topicPartition struct {
+	topic     string
+	partition int
+}
+
+type partitionOffsets struct {
+	pending []int64
+	done    map[int64]struct{}
+}
+
+// offsetTracker remembers fetched offsets per partition in fetch order, so that
+// workers finishing out of order never commit past an unfinished message.
+type offsetTracker struct {
+	mu         sync.Mutex
+	partitions map[topicPartition]*partitionOffsets
+}
+
+func newOffsetTracker() *offsetTracker {
+	return &offsetTracker{partitions: make(map[topicPartition]*partitionOffsets)}
+}
+
+func (t *offsetTracker) add(message kafka.Message) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	key := topicPartition{topic: message.Topic, partition: message.Partition}
+	p, ok := t.partitions[key]
+	if !ok {
+		p = &partitionOffsets{done: make(map[int64]struct{})}
+		t.partitions[key] = p
+	}
+	p.pending = append(p.pending, message.Offset)
+}
+
+// done marks message as handled. If this completes a contiguous run of handled
+// offsets at the head of its partition, the last message of that run is
+// returned so it can be committed.
+func (t *offsetTracker) done(message kafka.Message) (kafka.Message, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	key := topicPartition{topic: message.Topic, partition: message.Partition}
+	p, ok := t.partitions[key]
+	if !ok {
+		return kafka.Message{}, false
+	}
+	p.done[message.Offset] = struct{}{}
+
+	committed := int64(-1)
+	for len(p.pending) > 0 {
+		head := p.pending[0]
+		if _, ok := p.done[head]; !ok {
+			break
+		}
+		delete(p.done, head)
+		p.pending = p.pending[1:]
+		committed = head
+	}
+
+	if committed < 0 {
+		return kafka.Message{}, false
+	}
+
+	return kafka.Message{Topic: message.Topic, Partition: message.Partition, Offset: committed}, true
+}
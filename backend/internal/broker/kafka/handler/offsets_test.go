@@ -0,0 +1,45 @@
+package handler
+
+import (
+	"github.com/segmentio/kafka-go"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestOffsetTracker_CommitsContiguousPrefix(t *testing.T) {
+	tracker := newOffsetTracker()
+
+	msg := func(partition int, offset int64) kafka.Message {
+		return kafka.Message{Topic: "orders", Partition: partition, Offset: offset}
+	}
+
+	for offset := int64(10); offset <= 13; offset++ {
+		tracker.add(msg(0, offset))
+	}
+	tracker.add(msg(1, 5))
+
+	_, ok := tracker.done(msg(0, 12))
+	assert.False(t, ok, "offset 10 is still in flight")
+
+	_, ok = tracker.done(msg(0, 11))
+	assert.False(t, ok, "offset 10 is still in flight")
+
+	committable, ok := tracker.done(msg(0, 10))
+	assert.True(t, ok)
+	assert.Equal(t, msg(0, 12), committable)
+
+	committable, ok = tracker.done(msg(1, 5))
+	assert.True(t, ok)
+	assert.Equal(t, msg(1, 5), committable)
+
+	committable, ok = tracker.done(msg(0, 13))
+	assert.True(t, ok)
+	assert.Equal(t, msg(0, 13), committable)
+}
+
+func TestOffsetTracker_UnknownPartition(t *testing.T) {
+	tracker := newOffsetTracker()
+
+	_, ok := tracker.done(kafka.Message{Topic: "orders", Partition: 3, Offset: 1})
+	assert.False(t, ok)
+}
@@ -4,32 +4,62 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"github.com/ilam072/wbtech-l0/backend/internal/broker/kafka/dlq"
+	"github.com/ilam072/wbtech-l0/backend/internal/metrics"
 	"github.com/ilam072/wbtech-l0/backend/internal/service"
+	"github.com/ilam072/wbtech-l0/backend/internal/tracing"
 	"github.com/ilam072/wbtech-l0/backend/internal/types/dto"
+	"github.com/ilam072/wbtech-l0/backend/pkg/e"
 	"github.com/ilam072/wbtech-l0/backend/pkg/logger/sl"
+	"github.com/ilam072/wbtech-l0/backend/pkg/retry"
 	"github.com/segmentio/kafka-go"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"hash/fnv"
 	"log/slog"
+	"strconv"
+	"sync"
+	"time"
 )
 
+const workerQueueSize = 64
+
+// ErrDrainTimeout is returned by Start when the messages fetched before
+// shutdown were not handled within the drain timeout.
+var ErrDrainTimeout = errors.New("in-flight messages were not drained in time")
+
 //go:generate mockgen -source=order_handler.go -destination=../../../../mocks/kafka/mock_order_handler.go -package kafka
 type Consumer interface {
-	Consume(context.Context) (kafka.Message, error)
+	Fetch(context.Context) (kafka.Message, error)
+	Commit(context.Context, ...kafka.Message) error
 	Close() error
 }
 
 type Service interface {
-	CreateOrder(context.Context, dto.Order) error
+	UpsertOrder(context.Context, dto.Order) (service.UpsertOutcome, error)
 }
 
 type Validator interface {
 	Validate(i interface{}) error
 }
 
+type DeadLetterProducer interface {
+	Publish(ctx context.Context, msg kafka.Message, f dlq.Failure) error
+}
+
 type OrderConsumerHandler struct {
-	log       *slog.Logger
-	consumer  Consumer
-	service   Service
-	validator Validator
+	log         *slog.Logger
+	consumer    Consumer
+	service     Service
+	validator   Validator
+	deadLetter  DeadLetterProducer
+	retryPolicy retry.Policy
+	workers     int
+	// drainTimeout bounds how long handling of already fetched messages may
+	// take after Start's context is cancelled. Zero means no limit.
+	drainTimeout time.Duration
+	commitMu     sync.Mutex
 }
 
 func NewOrderConsumerHandler(
@@ -37,15 +67,30 @@ func NewOrderConsumerHandler(
 	c Consumer,
 	s Service,
 	v Validator,
+	d DeadLetterProducer,
+	p retry.Policy,
+	workers int,
+	drainTimeout time.Duration,
 ) *OrderConsumerHandler {
 	return &OrderConsumerHandler{
-		log:       log,
-		consumer:  c,
-		service:   s,
-		validator: v,
+		log:          log,
+		consumer:     c,
+		service:      s,
+		validator:    v,
+		deadLetter:   d,
+		retryPolicy:  p,
+		workers:      workers,
+		drainTimeout: drainTimeout,
 	}
 }
 
+// Start fetches messages and fans them out to a pool of workers. Messages with
+// the same key (or, for messages without a key, from the same partition) are
+// always handled by the same worker, so they are processed in order.
+//
+// Cancelling ctx only stops fetching: messages already fetched are still
+// handled and committed, for at most the drain timeout. Messages left over
+// when it expires are not committed and ErrDrainTimeout is returned.
 func (h *OrderConsumerHandler) Start(ctx context.Context) error {
 	const op = "kafka.handler.Start()"
 
@@ -53,37 +98,270 @@ func (h *OrderConsumerHandler) Start(ctx context.Context) error {
 		slog.String("op", op),
 	)
 
-	for {
+	fetchCtx, stopFetching := context.WithCancel(ctx)
+	defer stopFetching()
+
+	workCtx, abort := context.WithCancel(context.WithoutCancel(ctx))
+	defer abort()
+
+	fatal := make(chan error, 1)
+	fail := func(err error) {
 		select {
-		case <-ctx.Done():
-			log.Info("kafka consumer shutting down...")
-			return nil
+		case fatal <- err:
 		default:
-			message, err := h.consumer.Consume(ctx)
-			if err != nil {
-				log.Warn("failed to read message", slog.String("error", err.Error()))
-				continue
-			}
+		}
+		stopFetching()
+		abort()
+	}
 
-			order := dto.Order{}
+	tracker := newOffsetTracker()
+	queues := make([]chan kafka.Message, max(h.workers, 1))
 
-			if err := json.Unmarshal(message.Value, &order); err != nil {
-				log.Error("failed to decode json message to order", sl.Err(err))
-				continue
-			}
+	var wg sync.WaitGroup
+	for i := range queues {
+		queues[i] = make(chan kafka.Message, workerQueueSize)
 
-			if err := h.validator.Validate(order); err != nil {
-				log.Warn("failed to validate order", slog.String("error", err.Error()))
-				continue
+		wg.Add(1)
+		go func(queue <-chan kafka.Message) {
+			defer wg.Done()
+			h.work(workCtx, tracker, queue, fail)
+		}(queues[i])
+	}
+
+	failures := 0
+	for fetchCtx.Err() == nil {
+		message, err := h.consumer.Fetch(fetchCtx)
+		if err != nil {
+			if fetchCtx.Err() == nil {
+				failures++
+				log.Warn("failed to fetch message", sl.Err(err), slog.Int("failures", failures))
+				// An unreachable broker fails every fetch at once: back off
+				// instead of polling it in a busy loop.
+				retry.Wait(fetchCtx, h.retryPolicy.Backoff(failures))
 			}
+			continue
+		}
+		failures = 0
+
+		metrics.KafkaMessagesConsumed.Inc()
+		metrics.ObserveLag(message)
+		tracker.add(message)
+
+		select {
+		case queues[workerIndex(message, len(queues))] <- message:
+		case <-fetchCtx.Done():
+			// not queued: left uncommitted so it is redelivered
+		}
+	}
+
+	log.Info("kafka consumer stopped fetching, draining in-flight messages...")
 
-			if err = h.service.CreateOrder(ctx, order); err != nil {
-				if errors.Is(err, service.ErrOrderExists) {
-					log.Warn("order with such uid already exists", slog.String("error", err.Error()))
-					continue
-				}
-				log.Error("failed to create order", sl.Err(err))
+	for _, queue := range queues {
+		close(queue)
+	}
+	drained := h.drain(&wg)
+	if !drained {
+		abort()
+		wg.Wait()
+	}
+
+	select {
+	case err := <-fatal:
+		return e.Wrap(op, err)
+	default:
+	}
+
+	if !drained {
+		log.Error("kafka consumer drain timed out", slog.Duration("timeout", h.drainTimeout))
+		return e.Wrap(op, ErrDrainTimeout)
+	}
+
+	log.Info("kafka consumer shutting down...")
+	return nil
+}
+
+// drain waits for the workers to finish and reports whether they did so
+// within the drain timeout.
+func (h *OrderConsumerHandler) drain(wg *sync.WaitGroup) bool {
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	if h.drainTimeout <= 0 {
+		<-done
+		return true
+	}
+
+	timer := time.NewTimer(h.drainTimeout)
+	defer timer.Stop()
+
+	select {
+	case <-done:
+		return true
+	case <-timer.C:
+		return false
+	}
+}
+
+func (h *OrderConsumerHandler) work(
+	ctx context.Context,
+	tracker *offsetTracker,
+	queue <-chan kafka.Message,
+	fail func(error),
+) {
+	for message := range queue {
+		if ctx.Err() != nil {
+			// aborted: leave the message uncommitted so it is redelivered
+			continue
+		}
+
+		if err := h.handleMessage(ctx, message); err != nil {
+			if ctx.Err() == nil {
+				fail(err)
 			}
+			continue
 		}
+
+		h.commit(ctx, tracker, message)
 	}
 }
+
+// commit marks message as handled and commits the highest offset of its
+// partition below which every fetched message has been handled.
+func (h *OrderConsumerHandler) commit(ctx context.Context, tracker *offsetTracker, message kafka.Message) {
+	h.commitMu.Lock()
+	defer h.commitMu.Unlock()
+
+	committable, ok := tracker.done(message)
+	if !ok {
+		return
+	}
+
+	if err := h.consumer.Commit(ctx, committable); err != nil {
+		h.log.Warn("failed to commit message offset",
+			slog.String("topic", committable.Topic),
+			slog.Int("partition", committable.Partition),
+			slog.Int64("offset", committable.Offset),
+			slog.String("error", err.Error()),
+		)
+	}
+}
+
+func workerIndex(message kafka.Message, workers int) int {
+	if len(message.Key) == 0 {
+		return message.Partition % workers
+	}
+
+	hash := fnv.New32a()
+	_, _ = hash.Write(message.Key)
+	return int(hash.Sum32() % uint32(workers))
+}
+
+// handleMessage returns nil once the message is either stored or dead-lettered,
+// i.e. when its offset is safe to commit.
+func (h *OrderConsumerHandler) handleMessage(ctx context.Context, message kafka.Message) (err error) {
+	const op = "kafka.handler.handleMessage()"
+
+	ctx, span := tracing.Tracer().Start(
+		tracing.ExtractMessage(ctx, message),
+		"kafka.consume "+message.Topic,
+		trace.WithSpanKind(trace.SpanKindConsumer),
+		trace.WithAttributes(
+			attribute.String("messaging.system", "kafka"),
+			attribute.String("messaging.destination.name", message.Topic),
+			attribute.String("messaging.kafka.message.key", string(message.Key)),
+			attribute.String("messaging.kafka.partition", strconv.Itoa(message.Partition)),
+			attribute.Int64("messaging.kafka.offset", message.Offset),
+		),
+	)
+	defer tracing.End(span, &err)
+
+	log := h.log.With(
+		slog.String("op", op),
+		slog.Int("partition", message.Partition),
+		slog.Int64("offset", message.Offset),
+	)
+
+	order, err := h.decode(ctx, message)
+	if err != nil {
+		log.Error("failed to decode json message to order", sl.Err(err))
+		return h.sendToDeadLetter(ctx, message, dlq.Failure{Stage: dlq.StageDecode, Err: err, Attempts: 1})
+	}
+	span.SetAttributes(attribute.String("order.uid", order.OrderUID))
+
+	if err := h.validate(ctx, order); err != nil {
+		log.Warn("failed to validate order", slog.String("error", err.Error()))
+		return h.sendToDeadLetter(ctx, message, dlq.Failure{Stage: dlq.StageValidate, Err: err, Attempts: 1})
+	}
+
+	var outcome service.UpsertOutcome
+	attempts, err := h.retryPolicy.Do(ctx, isTransient, func(ctx context.Context) error {
+		var err error
+		outcome, err = h.service.UpsertOrder(ctx, order)
+		if err != nil && isTransient(err) {
+			log.Warn("temporary failure storing order, will retry", slog.String("error", err.Error()))
+		}
+		return err
+	})
+	if err != nil {
+		if ctx.Err() != nil {
+			// aborted: leave the message uncommitted so it is redelivered
+			return err
+		}
+		log.Error("failed to store order", slog.Int("attempts", attempts), sl.Err(err))
+		return h.sendToDeadLetter(ctx, message, dlq.Failure{Stage: dlq.StageCreate, Err: err, Attempts: attempts})
+	}
+
+	switch outcome {
+	case service.UpsertUpdated:
+		log.Info("order replaced by a newer version", slog.Int64("version", order.Version))
+		metrics.KafkaMessagesProcessed.WithLabelValues(metrics.ResultUpdated).Inc()
+	case service.UpsertUnchanged:
+		log.Warn("order with such uid is already stored")
+		metrics.KafkaMessagesProcessed.WithLabelValues(metrics.ResultDuplicate).Inc()
+	case service.UpsertStale:
+		log.Warn("a newer version of the order is already stored", slog.Int64("version", order.Version))
+		metrics.KafkaMessagesProcessed.WithLabelValues(metrics.ResultStale).Inc()
+	default:
+		metrics.KafkaMessagesProcessed.WithLabelValues(metrics.ResultStored).Inc()
+	}
+	return nil
+}
+
+func (h *OrderConsumerHandler) decode(ctx context.Context, message kafka.Message) (order dto.Order, err error) {
+	_, span := tracing.Tracer().Start(ctx, "order.decode")
+	defer tracing.End(span, &err)
+
+	err = json.Unmarshal(message.Value, &order)
+	return order, err
+}
+
+func (h *OrderConsumerHandler) validate(ctx context.Context, order dto.Order) (err error) {
+	_, span := tracing.Tracer().Start(ctx, "order.validate")
+	defer tracing.End(span, &err)
+
+	return h.validator.Validate(order)
+}
+
+func isTransient(err error) bool {
+	return errors.Is(err, service.ErrTransient) || errors.Is(err, context.DeadlineExceeded)
+}
+
+func (h *OrderConsumerHandler) sendToDeadLetter(ctx context.Context, message kafka.Message, f dlq.Failure) error {
+	const op = "kafka.handler.sendToDeadLetter()"
+
+	metrics.KafkaMessagesFailed.WithLabelValues(f.Stage).Inc()
+
+	span := trace.SpanFromContext(ctx)
+	span.RecordError(f.Err)
+	span.SetStatus(codes.Error, "dead-lettered at "+f.Stage)
+
+	if err := h.deadLetter.Publish(ctx, message, f); err != nil {
+		return e.Wrap(op, err)
+	}
+
+	metrics.KafkaMessagesProcessed.WithLabelValues(metrics.ResultDeadLettered).Inc()
+	return nil
+}
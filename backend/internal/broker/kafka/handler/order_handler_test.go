@@ -4,19 +4,55 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"github.com/google/uuid"
+	"github.com/ilam072/wbtech-l0/backend/internal/broker/kafka/dlq"
 	"github.com/ilam072/wbtech-l0/backend/internal/service"
 	"github.com/ilam072/wbtech-l0/backend/internal/types/dto"
 	kafkamocks "github.com/ilam072/wbtech-l0/backend/mocks/kafka"
 	"github.com/ilam072/wbtech-l0/backend/pkg/logger/handlers/slogdiscard"
+	"github.com/ilam072/wbtech-l0/backend/pkg/retry"
 	"github.com/segmentio/kafka-go"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
 	"go.uber.org/mock/gomock"
+	"sync"
 	"testing"
 	"time"
 )
 
+var testRetryPolicy = retry.Policy{
+	MaxAttempts:    3,
+	InitialBackoff: time.Millisecond,
+	MaxBackoff:     5 * time.Millisecond,
+	Multiplier:     2,
+}
+
+// blockUntilCancelled makes every further Fetch call wait for the handler to stop.
+func blockUntilCancelled(consumer *kafkamocks.MockConsumer) {
+	consumer.EXPECT().Fetch(gomock.Any()).DoAndReturn(func(ctx context.Context) (kafka.Message, error) {
+		<-ctx.Done()
+		return kafka.Message{}, ctx.Err()
+	}).AnyTimes()
+}
+
+func committed(message kafka.Message) kafka.Message {
+	return kafka.Message{Topic: message.Topic, Partition: message.Partition, Offset: message.Offset}
+}
+
+func orderMessage(t *testing.T, order dto.Order, offset int64) kafka.Message {
+	value, err := json.Marshal(order)
+	require.NoError(t, err)
+
+	return kafka.Message{Topic: "orders", Offset: offset, Key: []byte(order.OrderUID), Value: value}
+}
+
 func TestOrderConsumerHandler_Start_Success(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -25,8 +61,9 @@ func TestOrderConsumerHandler_Start_Success(t *testing.T) {
 	consumer := kafkamocks.NewMockConsumer(ctrl)
 	mockService := kafkamocks.NewMockService(ctrl)
 	validator := kafkamocks.NewMockValidator(ctrl)
+	deadLetter := kafkamocks.NewMockDeadLetterProducer(ctrl)
 
-	h := NewOrderConsumerHandler(log, consumer, mockService, validator)
+	h := NewOrderConsumerHandler(log, consumer, mockService, validator, deadLetter, testRetryPolicy, 1, 0)
 
 	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*500)
 	defer cancel()
@@ -34,20 +71,21 @@ func TestOrderConsumerHandler_Start_Success(t *testing.T) {
 	order := dto.Order{
 		OrderUID: uuid.New().String(),
 	}
-	bytes, err := json.Marshal(order)
-	require.NoError(t, err)
+	message := orderMessage(t, order, 1)
 
 	gomock.InOrder(
-		consumer.EXPECT().Consume(gomock.Any()).Return(kafka.Message{Value: bytes}, nil),
+		consumer.EXPECT().Fetch(gomock.Any()).Return(message, nil),
 		validator.EXPECT().Validate(order).Return(nil),
-		mockService.EXPECT().CreateOrder(gomock.Any(), order).Return(nil),
-		consumer.EXPECT().Consume(gomock.Any()).DoAndReturn(func(ctx context.Context) (kafka.Message, error) {
-			cancel()
-			return kafka.Message{}, context.Canceled
-		}),
+		mockService.EXPECT().UpsertOrder(gomock.Any(), order).Return(service.UpsertInserted, nil),
+		consumer.EXPECT().Commit(gomock.Any(), committed(message)).DoAndReturn(
+			func(context.Context, ...kafka.Message) error {
+				cancel()
+				return nil
+			}),
 	)
+	blockUntilCancelled(consumer)
 
-	err = h.Start(ctx)
+	err := h.Start(ctx)
 	assert.NoError(t, err)
 }
 
@@ -70,10 +108,42 @@ func TestOrderConsumerHandler_Start_ContextCancelled(t *testing.T) {
 	ctx, cancel := context.WithCancel(context.Background())
 	cancel()
 
+	consumer.EXPECT().Fetch(gomock.Any()).Times(0)
+
 	err := handler.Start(ctx)
 	assert.NoError(t, err)
+}
+
+func TestOrderConsumerHandler_Start_FetchErrorBacksOff(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	log := slogdiscard.NewDiscardLogger()
+	consumer := kafkamocks.NewMockConsumer(ctrl)
+	mockService := kafkamocks.NewMockService(ctrl)
+	validator := kafkamocks.NewMockValidator(ctrl)
+	deadLetter := kafkamocks.NewMockDeadLetterProducer(ctrl)
+
+	policy := retry.Policy{InitialBackoff: time.Hour, MaxBackoff: time.Hour, Multiplier: 2}
+	h := NewOrderConsumerHandler(log, consumer, mockService, validator, deadLetter, policy, 1, 0)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
 
-	consumer.EXPECT().Consume(gomock.Any()).Times(0)
+	consumer.EXPECT().Fetch(gomock.Any()).DoAndReturn(func(context.Context) (kafka.Message, error) {
+		time.AfterFunc(50*time.Millisecond, cancel)
+		return kafka.Message{}, errors.New("broker unavailable")
+	}).Times(1)
+
+	done := make(chan error, 1)
+	go func() { done <- h.Start(ctx) }()
+
+	select {
+	case err := <-done:
+		assert.NoError(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("Start did not return after the context was cancelled during the backoff")
+	}
 }
 
 func TestOrderConsumerHandler_Start_InvalidJSON(t *testing.T) {
@@ -83,27 +153,40 @@ func TestOrderConsumerHandler_Start_InvalidJSON(t *testing.T) {
 	consumer := kafkamocks.NewMockConsumer(ctrl)
 	validator := kafkamocks.NewMockValidator(ctrl)
 	mockService := kafkamocks.NewMockService(ctrl)
+	deadLetter := kafkamocks.NewMockDeadLetterProducer(ctrl)
 	logger := slogdiscard.NewDiscardLogger()
 
 	h := &OrderConsumerHandler{
-		consumer:  consumer,
-		validator: validator,
-		service:   mockService,
-		log:       logger,
+		consumer:   consumer,
+		validator:  validator,
+		service:    mockService,
+		deadLetter: deadLetter,
+		log:        logger,
 	}
 
 	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
 
-	invalidMessage := []byte("invalid json")
-	consumer.EXPECT().Consume(gomock.Any()).Return(kafka.Message{Value: invalidMessage}, nil)
-	consumer.EXPECT().Consume(gomock.Any()).DoAndReturn(func(ctx context.Context) (kafka.Message, error) {
-		cancel()
-		return kafka.Message{}, context.Canceled
-	})
+	invalidMessage := kafka.Message{Topic: "orders", Offset: 3, Value: []byte("invalid json")}
+
+	gomock.InOrder(
+		consumer.EXPECT().Fetch(gomock.Any()).Return(invalidMessage, nil),
+		deadLetter.EXPECT().Publish(gomock.Any(), invalidMessage, gomock.Any()).
+			DoAndReturn(func(ctx context.Context, msg kafka.Message, f dlq.Failure) error {
+				assert.Equal(t, dlq.StageDecode, f.Stage)
+				assert.Error(t, f.Err)
+				return nil
+			}),
+		consumer.EXPECT().Commit(gomock.Any(), committed(invalidMessage)).DoAndReturn(
+			func(context.Context, ...kafka.Message) error {
+				cancel()
+				return nil
+			}),
+	)
+	blockUntilCancelled(consumer)
 
 	err := h.Start(ctx)
 	assert.NoError(t, err)
-
 }
 
 func TestOrderConsumerHandler_Start_ValidationFails(t *testing.T) {
@@ -113,13 +196,15 @@ func TestOrderConsumerHandler_Start_ValidationFails(t *testing.T) {
 	consumer := kafkamocks.NewMockConsumer(ctrl)
 	validator := kafkamocks.NewMockValidator(ctrl)
 	mockService := kafkamocks.NewMockService(ctrl)
+	deadLetter := kafkamocks.NewMockDeadLetterProducer(ctrl)
 	logger := slogdiscard.NewDiscardLogger()
 
 	h := &OrderConsumerHandler{
-		consumer:  consumer,
-		validator: validator,
-		service:   mockService,
-		log:       logger,
+		consumer:   consumer,
+		validator:  validator,
+		service:    mockService,
+		deadLetter: deadLetter,
+		log:        logger,
 	}
 
 	ctx, cancel := context.WithCancel(context.Background())
@@ -128,23 +213,30 @@ func TestOrderConsumerHandler_Start_ValidationFails(t *testing.T) {
 	order := dto.Order{
 		OrderUID: "invalid uuid",
 	}
-	message, err := json.Marshal(order)
-	require.NoError(t, err)
+	message := orderMessage(t, order, 4)
+	validationErr := errors.New("validation error")
 
 	gomock.InOrder(
-		consumer.EXPECT().Consume(gomock.Any()).Return(kafka.Message{Value: message}, nil),
-		validator.EXPECT().Validate(order).Return(errors.New("validation error")),
-		consumer.EXPECT().Consume(gomock.Any()).DoAndReturn(func(ctx context.Context) (kafka.Message, error) {
-			cancel()
-			return kafka.Message{}, context.Canceled
-		}),
+		consumer.EXPECT().Fetch(gomock.Any()).Return(message, nil),
+		validator.EXPECT().Validate(order).Return(validationErr),
+		deadLetter.EXPECT().Publish(gomock.Any(), message, dlq.Failure{
+			Stage:    dlq.StageValidate,
+			Err:      validationErr,
+			Attempts: 1,
+		}).Return(nil),
+		consumer.EXPECT().Commit(gomock.Any(), committed(message)).DoAndReturn(
+			func(context.Context, ...kafka.Message) error {
+				cancel()
+				return nil
+			}),
 	)
+	blockUntilCancelled(consumer)
 
-	err = h.Start(ctx)
+	err := h.Start(ctx)
 	assert.NoError(t, err)
 }
 
-func TestOrderConsumerHandler_Start_CreateOrderFails(t *testing.T) {
+func TestOrderConsumerHandler_Start_DuplicateOrder(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
@@ -166,18 +258,372 @@ func TestOrderConsumerHandler_Start_CreateOrderFails(t *testing.T) {
 	order := dto.Order{
 		OrderUID: uuid.New().String(),
 	}
-	bytes, err := json.Marshal(order)
+	message := orderMessage(t, order, 5)
+
+	gomock.InOrder(
+		consumer.EXPECT().Fetch(gomock.Any()).Return(message, nil),
+		validator.EXPECT().Validate(order).Return(nil),
+		mockService.EXPECT().UpsertOrder(gomock.Any(), order).Return(service.UpsertUnchanged, nil),
+		consumer.EXPECT().Commit(gomock.Any(), committed(message)).DoAndReturn(
+			func(context.Context, ...kafka.Message) error {
+				cancel()
+				return nil
+			}),
+	)
+	blockUntilCancelled(consumer)
+
+	err := h.Start(ctx)
+	assert.NoError(t, err)
+}
+
+func TestOrderConsumerHandler_Start_StoreOrderFails_DeadLetter(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	consumer := kafkamocks.NewMockConsumer(ctrl)
+	validator := kafkamocks.NewMockValidator(ctrl)
+	mockService := kafkamocks.NewMockService(ctrl)
+	deadLetter := kafkamocks.NewMockDeadLetterProducer(ctrl)
+	logger := slogdiscard.NewDiscardLogger()
+
+	h := NewOrderConsumerHandler(logger, consumer, mockService, validator, deadLetter, testRetryPolicy, 1, 0)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	order := dto.Order{
+		OrderUID: uuid.New().String(),
+	}
+	message := orderMessage(t, order, 42)
+	message.Partition = 2
+	createErr := errors.New("db error")
+
+	gomock.InOrder(
+		consumer.EXPECT().Fetch(gomock.Any()).Return(message, nil),
+		validator.EXPECT().Validate(order).Return(nil),
+		mockService.EXPECT().UpsertOrder(gomock.Any(), order).Return(service.UpsertOutcome(""), createErr),
+		deadLetter.EXPECT().Publish(gomock.Any(), message, dlq.Failure{
+			Stage:    dlq.StageCreate,
+			Err:      createErr,
+			Attempts: 1,
+		}).Return(nil),
+		consumer.EXPECT().Commit(gomock.Any(), committed(message)).DoAndReturn(
+			func(context.Context, ...kafka.Message) error {
+				cancel()
+				return nil
+			}),
+	)
+	blockUntilCancelled(consumer)
+
+	err := h.Start(ctx)
+	assert.NoError(t, err)
+}
+
+func TestOrderConsumerHandler_Start_DeadLetterFails_NoCommit(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	consumer := kafkamocks.NewMockConsumer(ctrl)
+	validator := kafkamocks.NewMockValidator(ctrl)
+	mockService := kafkamocks.NewMockService(ctrl)
+	deadLetter := kafkamocks.NewMockDeadLetterProducer(ctrl)
+	logger := slogdiscard.NewDiscardLogger()
+
+	h := NewOrderConsumerHandler(logger, consumer, mockService, validator, deadLetter, testRetryPolicy, 1, 0)
+
+	message := kafka.Message{Value: []byte("invalid json"), Partition: 1, Offset: 7}
+
+	gomock.InOrder(
+		consumer.EXPECT().Fetch(gomock.Any()).Return(message, nil),
+		deadLetter.EXPECT().Publish(gomock.Any(), message, gomock.Any()).Return(errors.New("broker unavailable")),
+	)
+	blockUntilCancelled(consumer)
+	consumer.EXPECT().Commit(gomock.Any(), gomock.Any()).Times(0)
+
+	err := h.Start(context.Background())
+	assert.ErrorContains(t, err, "broker unavailable")
+}
+
+func TestOrderConsumerHandler_Start_RetriesTransientErrors(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	consumer := kafkamocks.NewMockConsumer(ctrl)
+	validator := kafkamocks.NewMockValidator(ctrl)
+	mockService := kafkamocks.NewMockService(ctrl)
+	deadLetter := kafkamocks.NewMockDeadLetterProducer(ctrl)
+	logger := slogdiscard.NewDiscardLogger()
+
+	h := NewOrderConsumerHandler(logger, consumer, mockService, validator, deadLetter, testRetryPolicy, 1, 0)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	order := dto.Order{
+		OrderUID: uuid.New().String(),
+	}
+	message := orderMessage(t, order, 8)
+	transientErr := fmt.Errorf("%w: connection refused", service.ErrTransient)
+
+	gomock.InOrder(
+		consumer.EXPECT().Fetch(gomock.Any()).Return(message, nil),
+		validator.EXPECT().Validate(order).Return(nil),
+		mockService.EXPECT().UpsertOrder(gomock.Any(), order).Return(service.UpsertOutcome(""), transientErr),
+		mockService.EXPECT().UpsertOrder(gomock.Any(), order).Return(service.UpsertOutcome(""), transientErr),
+		mockService.EXPECT().UpsertOrder(gomock.Any(), order).Return(service.UpsertInserted, nil),
+		consumer.EXPECT().Commit(gomock.Any(), committed(message)).DoAndReturn(
+			func(context.Context, ...kafka.Message) error {
+				cancel()
+				return nil
+			}),
+	)
+	blockUntilCancelled(consumer)
+	deadLetter.EXPECT().Publish(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+	err := h.Start(ctx)
+	assert.NoError(t, err)
+}
+
+func TestOrderConsumerHandler_Start_RetryBudgetExhausted(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	consumer := kafkamocks.NewMockConsumer(ctrl)
+	validator := kafkamocks.NewMockValidator(ctrl)
+	mockService := kafkamocks.NewMockService(ctrl)
+	deadLetter := kafkamocks.NewMockDeadLetterProducer(ctrl)
+	logger := slogdiscard.NewDiscardLogger()
+
+	h := NewOrderConsumerHandler(logger, consumer, mockService, validator, deadLetter, testRetryPolicy, 1, 0)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	order := dto.Order{
+		OrderUID: uuid.New().String(),
+	}
+	message := orderMessage(t, order, 9)
+	transientErr := fmt.Errorf("%w: deadlock detected", service.ErrTransient)
+
+	gomock.InOrder(
+		consumer.EXPECT().Fetch(gomock.Any()).Return(message, nil),
+		validator.EXPECT().Validate(order).Return(nil),
+		mockService.EXPECT().UpsertOrder(gomock.Any(), order).Return(service.UpsertOutcome(""), transientErr).Times(testRetryPolicy.MaxAttempts),
+		deadLetter.EXPECT().Publish(gomock.Any(), message, dlq.Failure{
+			Stage:    dlq.StageCreate,
+			Err:      transientErr,
+			Attempts: testRetryPolicy.MaxAttempts,
+		}).Return(nil),
+		consumer.EXPECT().Commit(gomock.Any(), committed(message)).DoAndReturn(
+			func(context.Context, ...kafka.Message) error {
+				cancel()
+				return nil
+			}),
+	)
+	blockUntilCancelled(consumer)
+
+	err := h.Start(ctx)
+	assert.NoError(t, err)
+}
+
+func TestOrderConsumerHandler_Start_ParallelWorkers(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	consumer := kafkamocks.NewMockConsumer(ctrl)
+	validator := kafkamocks.NewMockValidator(ctrl)
+	mockService := kafkamocks.NewMockService(ctrl)
+	deadLetter := kafkamocks.NewMockDeadLetterProducer(ctrl)
+	logger := slogdiscard.NewDiscardLogger()
+
+	h := NewOrderConsumerHandler(logger, consumer, mockService, validator, deadLetter, testRetryPolicy, 4, 0)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	const partitions, perPartition = 3, 20
+
+	var messages []kafka.Message
+	for offset := int64(0); offset < perPartition; offset++ {
+		for partition := 0; partition < partitions; partition++ {
+			order := dto.Order{OrderUID: uuid.New().String(), TrackNumber: fmt.Sprintf("p%d", partition)}
+			message := orderMessage(t, order, offset)
+			message.Partition = partition
+			message.Key = nil
+			messages = append(messages, message)
+		}
+	}
+
+	next := 0
+	consumer.EXPECT().Fetch(gomock.Any()).DoAndReturn(func(ctx context.Context) (kafka.Message, error) {
+		if next == len(messages) {
+			<-ctx.Done()
+			return kafka.Message{}, ctx.Err()
+		}
+		next++
+		return messages[next-1], nil
+	}).AnyTimes()
+	validator.EXPECT().Validate(gomock.Any()).Return(nil).Times(len(messages))
+
+	var (
+		mu       sync.Mutex
+		created  = make(map[string][]string)
+		lastSeen = make(map[int]int64)
+	)
+	mockService.EXPECT().UpsertOrder(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, order dto.Order) (service.UpsertOutcome, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			created[order.TrackNumber] = append(created[order.TrackNumber], order.OrderUID)
+			return service.UpsertInserted, nil
+		}).Times(len(messages))
+	consumer.EXPECT().Commit(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, msgs ...kafka.Message) error {
+			mu.Lock()
+			defer mu.Unlock()
+			for _, m := range msgs {
+				assert.Greater(t, m.Offset, lastSeen[m.Partition]-1, "commits must not go backwards")
+				lastSeen[m.Partition] = m.Offset
+			}
+			if len(lastSeen) == partitions {
+				done := true
+				for _, offset := range lastSeen {
+					done = done && offset == perPartition-1
+				}
+				if done {
+					cancel()
+				}
+			}
+			return nil
+		}).MinTimes(partitions)
+
+	err := h.Start(ctx)
 	require.NoError(t, err)
+	require.NotErrorIs(t, ctx.Err(), context.DeadlineExceeded, "all partitions should be committed before the timeout")
+
+	for partition := 0; partition < partitions; partition++ {
+		var expected []string
+		for _, message := range messages {
+			if message.Partition == partition {
+				var order dto.Order
+				require.NoError(t, json.Unmarshal(message.Value, &order))
+				expected = append(expected, order.OrderUID)
+			}
+		}
+		assert.Equal(t, expected, created[fmt.Sprintf("p%d", partition)], "orders of a partition are processed in order")
+	}
+}
+
+func TestOrderConsumerHandler_handleMessage_Tracing(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	defer otel.SetTracerProvider(noop.NewTracerProvider())
+
+	mockService := kafkamocks.NewMockService(ctrl)
+	validator := kafkamocks.NewMockValidator(ctrl)
+
+	h := NewOrderConsumerHandler(slogdiscard.NewDiscardLogger(), nil, mockService, validator, nil, testRetryPolicy, 1, 0)
+
+	order := dto.Order{OrderUID: uuid.New().String()}
+	message := orderMessage(t, order, 7)
+	message.Headers = []kafka.Header{
+		{Key: "traceparent", Value: []byte("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")},
+	}
+
+	validator.EXPECT().Validate(order).Return(nil)
+	mockService.EXPECT().UpsertOrder(gomock.Any(), order).DoAndReturn(func(ctx context.Context, _ dto.Order) (service.UpsertOutcome, error) {
+		assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", trace.SpanContextFromContext(ctx).TraceID().String())
+		return service.UpsertInserted, nil
+	})
+
+	require.NoError(t, h.handleMessage(context.Background(), message))
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 3)
+
+	consume := spans[2]
+	assert.Equal(t, "kafka.consume orders", consume.Name)
+	assert.Equal(t, "00f067aa0ba902b7", consume.Parent.SpanID().String())
+	for _, span := range spans {
+		assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", span.SpanContext.TraceID().String())
+	}
+	assert.Equal(t, "order.decode", spans[0].Name)
+	assert.Equal(t, consume.SpanContext.SpanID(), spans[0].Parent.SpanID())
+	assert.Equal(t, "order.validate", spans[1].Name)
+	assert.Equal(t, consume.SpanContext.SpanID(), spans[1].Parent.SpanID())
+}
+
+func TestOrderConsumerHandler_Start_DrainsInFlightMessages(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	consumer := kafkamocks.NewMockConsumer(ctrl)
+	validator := kafkamocks.NewMockValidator(ctrl)
+	mockService := kafkamocks.NewMockService(ctrl)
+	deadLetter := kafkamocks.NewMockDeadLetterProducer(ctrl)
+	logger := slogdiscard.NewDiscardLogger()
+
+	h := NewOrderConsumerHandler(logger, consumer, mockService, validator, deadLetter, testRetryPolicy, 1, time.Second)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	order := dto.Order{OrderUID: uuid.New().String()}
+	message := orderMessage(t, order, 3)
 
 	gomock.InOrder(
-		consumer.EXPECT().Consume(gomock.Any()).Return(kafka.Message{Value: bytes}, nil),
+		consumer.EXPECT().Fetch(gomock.Any()).Return(message, nil),
 		validator.EXPECT().Validate(order).Return(nil),
-		mockService.EXPECT().CreateOrder(gomock.Any(), order).Return(service.ErrOrderExists),
-		consumer.EXPECT().Consume(gomock.Any()).DoAndReturn(func(ctx context.Context) (kafka.Message, error) {
+		mockService.EXPECT().UpsertOrder(gomock.Any(), order).DoAndReturn(func(ctx context.Context, _ dto.Order) (service.UpsertOutcome, error) {
+			// shutdown is requested while the order is being stored
 			cancel()
-			return kafka.Message{}, context.Canceled
+			time.Sleep(20 * time.Millisecond)
+			return service.UpsertInserted, ctx.Err()
 		}),
+		consumer.EXPECT().Commit(gomock.Any(), committed(message)).Return(nil),
 	)
+	blockUntilCancelled(consumer)
+
+	err := h.Start(ctx)
+	assert.NoError(t, err)
+}
 
-	err = h.Start(ctx)
+func TestOrderConsumerHandler_Start_DrainTimeout(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	consumer := kafkamocks.NewMockConsumer(ctrl)
+	validator := kafkamocks.NewMockValidator(ctrl)
+	mockService := kafkamocks.NewMockService(ctrl)
+	deadLetter := kafkamocks.NewMockDeadLetterProducer(ctrl)
+	logger := slogdiscard.NewDiscardLogger()
+
+	h := NewOrderConsumerHandler(logger, consumer, mockService, validator, deadLetter, testRetryPolicy, 1, 20*time.Millisecond)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	order := dto.Order{OrderUID: uuid.New().String()}
+	message := orderMessage(t, order, 4)
+
+	gomock.InOrder(
+		consumer.EXPECT().Fetch(gomock.Any()).Return(message, nil),
+		validator.EXPECT().Validate(order).Return(nil),
+		mockService.EXPECT().UpsertOrder(gomock.Any(), order).DoAndReturn(func(ctx context.Context, _ dto.Order) (service.UpsertOutcome, error) {
+			cancel()
+			<-ctx.Done()
+			return service.UpsertOutcome(""), ctx.Err()
+		}),
+	)
+	blockUntilCancelled(consumer)
+	consumer.EXPECT().Commit(gomock.Any(), gomock.Any()).Times(0)
+	deadLetter.EXPECT().Publish(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+	err := h.Start(ctx)
+	assert.ErrorIs(t, err, ErrDrainTimeout)
 }
@@ -0,0 +1,216 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/ilam072/wbtech-l0/backend/internal/broker/kafka/dlq"
+	"github.com/ilam072/wbtech-l0/backend/internal/metrics"
+	"github.com/ilam072/wbtech-l0/backend/internal/service"
+	"github.com/ilam072/wbtech-l0/backend/internal/tracing"
+	"github.com/ilam072/wbtech-l0/backend/internal/types/dto"
+	"github.com/ilam072/wbtech-l0/backend/pkg/e"
+	"github.com/ilam072/wbtech-l0/backend/pkg/logger/sl"
+	"github.com/ilam072/wbtech-l0/backend/pkg/retry"
+	"github.com/segmentio/kafka-go"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"log/slog"
+	"strconv"
+	"time"
+)
+
+//go:generate mockgen -source=status_handler.go -destination=../../../../mocks/kafka/mock_status_handler.go -package kafka
+type StatusService interface {
+	UpdateOrderStatus(ctx context.Context, update dto.StatusUpdate) (dto.Order, error)
+}
+
+// StatusEventHandler applies order status events. Events are handled one at
+// a time in the order they were fetched, so the changes of an order are
+// never reordered.
+type StatusEventHandler struct {
+	log         *slog.Logger
+	consumer    Consumer
+	service     StatusService
+	validator   Validator
+	deadLetter  DeadLetterProducer
+	retryPolicy retry.Policy
+	// drainTimeout bounds how long the event being handled when Start's
+	// context is cancelled may take. Zero means no limit.
+	drainTimeout time.Duration
+}
+
+func NewStatusEventHandler(
+	log *slog.Logger,
+	c Consumer,
+	s StatusService,
+	v Validator,
+	d DeadLetterProducer,
+	p retry.Policy,
+	drainTimeout time.Duration,
+) *StatusEventHandler {
+	return &StatusEventHandler{
+		log:          log,
+		consumer:     c,
+		service:      s,
+		validator:    v,
+		deadLetter:   d,
+		retryPolicy:  p,
+		drainTimeout: drainTimeout,
+	}
+}
+
+// Start handles events until ctx is cancelled. An event being handled when
+// ctx is cancelled is still applied and committed, for at most the drain
+// timeout. If it expires the event is left uncommitted and ErrDrainTimeout is
+// returned.
+func (h *StatusEventHandler) Start(ctx context.Context) error {
+	const op = "kafka.handler.StatusEventHandler.Start()"
+
+	log := h.log.With(
+		slog.String("op", op),
+	)
+
+	failures := 0
+	for ctx.Err() == nil {
+		message, err := h.consumer.Fetch(ctx)
+		if err != nil {
+			if ctx.Err() == nil {
+				failures++
+				log.Warn("failed to fetch status event", sl.Err(err), slog.Int("failures", failures))
+				retry.Wait(ctx, h.retryPolicy.Backoff(failures))
+			}
+			continue
+		}
+		failures = 0
+		metrics.ObserveLag(message)
+
+		if err := h.handleAndCommit(ctx, message); err != nil {
+			if errors.Is(err, ErrDrainTimeout) {
+				log.Error("status event consumer drain timed out", slog.Duration("timeout", h.drainTimeout))
+			}
+			return e.Wrap(op, err)
+		}
+	}
+
+	log.Info("status event consumer shutting down...")
+	return nil
+}
+
+// handleAndCommit handles message and commits its offset. Handling outlives
+// ctx by at most the drain timeout.
+func (h *StatusEventHandler) handleAndCommit(ctx context.Context, message kafka.Message) error {
+	handleCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	defer cancel()
+
+	if h.drainTimeout > 0 {
+		stop := context.AfterFunc(ctx, func() {
+			timer := time.NewTimer(h.drainTimeout)
+			defer timer.Stop()
+
+			select {
+			case <-timer.C:
+				cancel()
+			case <-handleCtx.Done():
+			}
+		})
+		defer stop()
+	}
+
+	if err := h.handleEvent(handleCtx, message); err != nil {
+		if handleCtx.Err() != nil {
+			// aborted: leave the event uncommitted so it is redelivered
+			return ErrDrainTimeout
+		}
+		return err
+	}
+
+	if err := h.consumer.Commit(handleCtx, message); err != nil {
+		h.log.Warn("failed to commit status event offset",
+			slog.Int("partition", message.Partition),
+			slog.Int64("offset", message.Offset),
+			slog.String("error", err.Error()),
+		)
+	}
+	return nil
+}
+
+// handleEvent returns nil once the event is either applied or dead-lettered.
+func (h *StatusEventHandler) handleEvent(ctx context.Context, message kafka.Message) (err error) {
+	const op = "kafka.handler.handleEvent()"
+
+	ctx, span := tracing.Tracer().Start(
+		tracing.ExtractMessage(ctx, message),
+		"kafka.consume "+message.Topic,
+		trace.WithSpanKind(trace.SpanKindConsumer),
+		trace.WithAttributes(
+			attribute.String("messaging.system", "kafka"),
+			attribute.String("messaging.destination.name", message.Topic),
+			attribute.String("messaging.kafka.partition", strconv.Itoa(message.Partition)),
+			attribute.Int64("messaging.kafka.offset", message.Offset),
+		),
+	)
+	defer tracing.End(span, &err)
+
+	log := h.log.With(
+		slog.String("op", op),
+		slog.Int("partition", message.Partition),
+		slog.Int64("offset", message.Offset),
+	)
+
+	var update dto.StatusUpdate
+	if err := json.Unmarshal(message.Value, &update); err != nil {
+		log.Error("failed to decode status event", sl.Err(err))
+		return h.sendToDeadLetter(ctx, message, dlq.Failure{Stage: dlq.StageDecode, Err: err, Attempts: 1})
+	}
+	span.SetAttributes(
+		attribute.String("order.uid", update.OrderUID),
+		attribute.String("order.status", update.Status),
+	)
+
+	if err := h.validator.Validate(update); err != nil {
+		log.Warn("failed to validate status event", slog.String("error", err.Error()))
+		return h.sendToDeadLetter(ctx, message, dlq.Failure{Stage: dlq.StageValidate, Err: err, Attempts: 1})
+	}
+
+	attempts, err := h.retryPolicy.Do(ctx, isRetryableStatusErr, func(ctx context.Context) error {
+		_, err := h.service.UpdateOrderStatus(ctx, update)
+		if err != nil && isRetryableStatusErr(err) {
+			log.Warn("failed to apply status event, will retry", slog.String("error", err.Error()))
+		}
+		return err
+	})
+	if err != nil {
+		if ctx.Err() != nil {
+			// aborted: leave the event uncommitted so it is redelivered
+			return err
+		}
+		log.Error("failed to apply status event", slog.Int("attempts", attempts), sl.Err(err))
+		return h.sendToDeadLetter(ctx, message, dlq.Failure{Stage: dlq.StageUpdateStatus, Err: err, Attempts: attempts})
+	}
+
+	return nil
+}
+
+// isRetryableStatusErr also retries events for orders not stored yet, since
+// orders and their status events come from different topics, and events
+// that lost a race with a concurrent status change.
+func isRetryableStatusErr(err error) bool {
+	return isTransient(err) ||
+		errors.Is(err, service.ErrOrderNotFound) ||
+		errors.Is(err, service.ErrStatusConflict)
+}
+
+func (h *StatusEventHandler) sendToDeadLetter(ctx context.Context, message kafka.Message, f dlq.Failure) error {
+	const op = "kafka.handler.StatusEventHandler.sendToDeadLetter()"
+
+	span := trace.SpanFromContext(ctx)
+	span.RecordError(f.Err)
+	span.SetStatus(codes.Error, "dead-lettered at "+f.Stage)
+
+	if err := h.deadLetter.Publish(ctx, message, f); err != nil {
+		return e.Wrap(op, err)
+	}
+	return nil
+}
@@ -0,0 +1,221 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/ilam072/wbtech-l0/backend/internal/broker/kafka/dlq"
+	"github.com/ilam072/wbtech-l0/backend/internal/service"
+	"github.com/ilam072/wbtech-l0/backend/internal/types/dto"
+	kafkamocks "github.com/ilam072/wbtech-l0/backend/mocks/kafka"
+	"github.com/ilam072/wbtech-l0/backend/pkg/logger/handlers/slogdiscard"
+	"github.com/ilam072/wbtech-l0/backend/pkg/retry"
+	"github.com/segmentio/kafka-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"testing"
+	"time"
+)
+
+func statusMessage(t *testing.T, update dto.StatusUpdate, offset int64) kafka.Message {
+	value, err := json.Marshal(update)
+	require.NoError(t, err)
+
+	return kafka.Message{Topic: "order-status", Offset: offset, Key: []byte(update.OrderUID), Value: value}
+}
+
+func TestStatusEventHandler_Start_Applied(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	consumer := kafkamocks.NewMockConsumer(ctrl)
+	validator := kafkamocks.NewMockValidator(ctrl)
+	statusService := kafkamocks.NewMockStatusService(ctrl)
+	deadLetter := kafkamocks.NewMockDeadLetterProducer(ctrl)
+
+	h := NewStatusEventHandler(slogdiscard.NewDiscardLogger(), consumer, statusService, validator, deadLetter, testRetryPolicy, 0)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	update := dto.StatusUpdate{OrderUID: uuid.New().String(), Status: "paid"}
+	message := statusMessage(t, update, 1)
+
+	gomock.InOrder(
+		consumer.EXPECT().Fetch(gomock.Any()).Return(message, nil),
+		validator.EXPECT().Validate(update).Return(nil),
+		statusService.EXPECT().UpdateOrderStatus(gomock.Any(), update).Return(dto.Order{}, nil),
+		consumer.EXPECT().Commit(gomock.Any(), message).DoAndReturn(
+			func(context.Context, ...kafka.Message) error {
+				cancel()
+				return nil
+			}),
+	)
+	deadLetter.EXPECT().Publish(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+	err := h.Start(ctx)
+	assert.NoError(t, err)
+}
+
+func TestStatusEventHandler_Start_RetriesMissingOrder(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	consumer := kafkamocks.NewMockConsumer(ctrl)
+	validator := kafkamocks.NewMockValidator(ctrl)
+	statusService := kafkamocks.NewMockStatusService(ctrl)
+	deadLetter := kafkamocks.NewMockDeadLetterProducer(ctrl)
+
+	h := NewStatusEventHandler(slogdiscard.NewDiscardLogger(), consumer, statusService, validator, deadLetter, testRetryPolicy, 0)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	update := dto.StatusUpdate{OrderUID: uuid.New().String(), Status: "paid"}
+	message := statusMessage(t, update, 2)
+
+	gomock.InOrder(
+		consumer.EXPECT().Fetch(gomock.Any()).Return(message, nil),
+		validator.EXPECT().Validate(update).Return(nil),
+		statusService.EXPECT().UpdateOrderStatus(gomock.Any(), update).Return(dto.Order{}, service.ErrOrderNotFound),
+		statusService.EXPECT().UpdateOrderStatus(gomock.Any(), update).Return(dto.Order{}, nil),
+		consumer.EXPECT().Commit(gomock.Any(), message).DoAndReturn(
+			func(context.Context, ...kafka.Message) error {
+				cancel()
+				return nil
+			}),
+	)
+	deadLetter.EXPECT().Publish(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+	err := h.Start(ctx)
+	assert.NoError(t, err)
+}
+
+func TestStatusEventHandler_Start_InvalidTransition_DeadLetter(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	consumer := kafkamocks.NewMockConsumer(ctrl)
+	validator := kafkamocks.NewMockValidator(ctrl)
+	statusService := kafkamocks.NewMockStatusService(ctrl)
+	deadLetter := kafkamocks.NewMockDeadLetterProducer(ctrl)
+
+	h := NewStatusEventHandler(slogdiscard.NewDiscardLogger(), consumer, statusService, validator, deadLetter, testRetryPolicy, 0)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	update := dto.StatusUpdate{OrderUID: uuid.New().String(), Status: "paid"}
+	message := statusMessage(t, update, 3)
+	transitionErr := fmt.Errorf("%w: delivered -> paid", service.ErrInvalidTransition)
+
+	gomock.InOrder(
+		consumer.EXPECT().Fetch(gomock.Any()).Return(message, nil),
+		validator.EXPECT().Validate(update).Return(nil),
+		statusService.EXPECT().UpdateOrderStatus(gomock.Any(), update).Return(dto.Order{}, transitionErr),
+		deadLetter.EXPECT().Publish(gomock.Any(), message, dlq.Failure{
+			Stage:    dlq.StageUpdateStatus,
+			Err:      transitionErr,
+			Attempts: 1,
+		}).Return(nil),
+		consumer.EXPECT().Commit(gomock.Any(), message).DoAndReturn(
+			func(context.Context, ...kafka.Message) error {
+				cancel()
+				return nil
+			}),
+	)
+
+	err := h.Start(ctx)
+	assert.NoError(t, err)
+}
+
+func TestStatusEventHandler_Start_DeadLetterFails_NoCommit(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	consumer := kafkamocks.NewMockConsumer(ctrl)
+	validator := kafkamocks.NewMockValidator(ctrl)
+	statusService := kafkamocks.NewMockStatusService(ctrl)
+	deadLetter := kafkamocks.NewMockDeadLetterProducer(ctrl)
+
+	h := NewStatusEventHandler(slogdiscard.NewDiscardLogger(), consumer, statusService, validator, deadLetter, testRetryPolicy, 0)
+
+	message := kafka.Message{Topic: "order-status", Value: []byte("invalid json"), Offset: 4}
+
+	gomock.InOrder(
+		consumer.EXPECT().Fetch(gomock.Any()).Return(message, nil),
+		deadLetter.EXPECT().Publish(gomock.Any(), message, gomock.Any()).Return(errors.New("broker unavailable")),
+	)
+	consumer.EXPECT().Commit(gomock.Any(), gomock.Any()).Times(0)
+
+	err := h.Start(context.Background())
+	assert.ErrorContains(t, err, "broker unavailable")
+}
+
+func TestStatusEventHandler_Start_FetchErrorBacksOff(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	consumer := kafkamocks.NewMockConsumer(ctrl)
+	statusService := kafkamocks.NewMockStatusService(ctrl)
+	validator := kafkamocks.NewMockValidator(ctrl)
+	deadLetter := kafkamocks.NewMockDeadLetterProducer(ctrl)
+
+	policy := retry.Policy{InitialBackoff: time.Hour, MaxBackoff: time.Hour, Multiplier: 2}
+	h := NewStatusEventHandler(slogdiscard.NewDiscardLogger(), consumer, statusService, validator, deadLetter, policy, 0)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	consumer.EXPECT().Fetch(gomock.Any()).DoAndReturn(func(context.Context) (kafka.Message, error) {
+		time.AfterFunc(50*time.Millisecond, cancel)
+		return kafka.Message{}, errors.New("broker unavailable")
+	}).Times(1)
+
+	done := make(chan error, 1)
+	go func() { done <- h.Start(ctx) }()
+
+	select {
+	case err := <-done:
+		assert.NoError(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("Start did not return after the context was cancelled during the backoff")
+	}
+}
+
+func TestStatusEventHandler_Start_DrainTimeout(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	consumer := kafkamocks.NewMockConsumer(ctrl)
+	validator := kafkamocks.NewMockValidator(ctrl)
+	statusService := kafkamocks.NewMockStatusService(ctrl)
+	deadLetter := kafkamocks.NewMockDeadLetterProducer(ctrl)
+
+	h := NewStatusEventHandler(slogdiscard.NewDiscardLogger(), consumer, statusService, validator, deadLetter, testRetryPolicy, 20*time.Millisecond)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	update := dto.StatusUpdate{OrderUID: uuid.New().String(), Status: "paid"}
+	message := statusMessage(t, update, 5)
+
+	gomock.InOrder(
+		consumer.EXPECT().Fetch(gomock.Any()).Return(message, nil),
+		validator.EXPECT().Validate(update).Return(nil),
+		statusService.EXPECT().UpdateOrderStatus(gomock.Any(), update).DoAndReturn(
+			func(ctx context.Context, _ dto.StatusUpdate) (dto.Order, error) {
+				cancel()
+				<-ctx.Done()
+				return dto.Order{}, ctx.Err()
+			}),
+	)
+	consumer.EXPECT().Commit(gomock.Any(), gomock.Any()).Times(0)
+	deadLetter.EXPECT().Publish(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+	err := h.Start(ctx)
+	assert.ErrorIs(t, err, ErrDrainTimeout)
+}
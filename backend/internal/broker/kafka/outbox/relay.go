@@ -0,0 +1,213 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"github.com/ilam072/wbtech-l0/backend/internal/config"
+	"github.com/ilam072/wbtech-l0/backend/internal/metrics"
+	"github.com/ilam072/wbtech-l0/backend/internal/types/domain"
+	"github.com/ilam072/wbtech-l0/backend/pkg/e"
+	"github.com/ilam072/wbtech-l0/backend/pkg/logger/sl"
+	"github.com/ilam072/wbtech-l0/backend/pkg/retry"
+	"github.com/segmentio/kafka-go"
+	"log/slog"
+	"strconv"
+	"time"
+)
+
+const (
+	// HeaderEventID carries the outbox id of the event, consumers may use it
+	// to drop events delivered twice.
+	HeaderEventID   = "x-event-id"
+	HeaderEventType = "x-event-type"
+)
+
+//go:generate mockgen -source=relay.go -destination=../../../../mocks/outbox/mock_relay.go -package=outbox
+type Repo interface {
+	ClaimOutbox(ctx context.Context, limit int, lease time.Duration) ([]domain.OutboxEvent, error)
+	MarkOutboxSent(ctx context.Context, ids []int64) error
+	RetryOutbox(ctx context.Context, id int64, reason string, delay time.Duration) error
+	PruneOutbox(ctx context.Context, retention time.Duration) (int64, error)
+}
+
+type Writer interface {
+	WriteMessages(ctx context.Context, msgs ...kafka.Message) error
+}
+
+// Relay publishes the events of the outbox table to Kafka. An event is marked
+// as sent only after Kafka has acknowledged it, so it is published at least
+// once; failed events are retried with a growing delay. Published events are
+// deleted once they are older than the retention.
+type Relay struct {
+	log           *slog.Logger
+	repo          Repo
+	writer        Writer
+	batchSize     int
+	pollInterval  time.Duration
+	lease         time.Duration
+	retryPolicy   retry.Policy
+	retention     time.Duration
+	pruneInterval time.Duration
+}
+
+func New(log *slog.Logger, repo Repo, w Writer, cfg config.OutboxConfig) *Relay {
+	return &Relay{
+		log:          log,
+		repo:         repo,
+		writer:       w,
+		batchSize:    cfg.BatchSize,
+		pollInterval: cfg.PollInterval,
+		lease:        cfg.Lease,
+		retryPolicy: retry.Policy{
+			InitialBackoff: cfg.RetryInitialBackoff,
+			MaxBackoff:     cfg.RetryMaxBackoff,
+			Multiplier:     2,
+		},
+		retention:     cfg.Retention,
+		pruneInterval: cfg.PruneInterval,
+	}
+}
+
+// NewWriter returns a writer for topic that keeps the events of an order in
+// one partition.
+func NewWriter(topic string, addr ...string) *kafka.Writer {
+	return &kafka.Writer{
+		Addr:                   kafka.TCP(addr...),
+		Topic:                  topic,
+		Balancer:               &kafka.Hash{},
+		RequiredAcks:           kafka.RequireAll,
+		AllowAutoTopicCreation: true,
+	}
+}
+
+// Start publishes pending events every poll interval and prunes published
+// ones every prune interval until ctx is cancelled.
+func (r *Relay) Start(ctx context.Context) {
+	const op = "kafka.outbox.Relay.Start()"
+
+	log := r.log.With(
+		slog.String("op", op),
+	)
+
+	ticker := time.NewTicker(r.pollInterval)
+	defer ticker.Stop()
+
+	var pruned time.Time
+	for {
+		if err := r.publishPending(ctx); err != nil && ctx.Err() == nil {
+			log.Error("failed to publish outbox events", sl.Err(err))
+		}
+
+		if r.retention > 0 && time.Since(pruned) >= r.pruneInterval {
+			if err := r.prune(ctx); err != nil && ctx.Err() == nil {
+				log.Error("failed to prune outbox events", sl.Err(err))
+			}
+			pruned = time.Now()
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// prune deletes the events published more than the retention ago.
+func (r *Relay) prune(ctx context.Context) error {
+	const op = "kafka.outbox.Relay.prune()"
+
+	n, err := r.repo.PruneOutbox(ctx, r.retention)
+	if err != nil {
+		return e.Wrap(op, err)
+	}
+	metrics.OutboxEventsPruned.Add(float64(n))
+
+	return nil
+}
+
+// publishPending publishes batches until the outbox has no ready events or a
+// batch fails.
+func (r *Relay) publishPending(ctx context.Context) error {
+	for ctx.Err() == nil {
+		n, err := r.publishBatch(ctx)
+		if err != nil {
+			return err
+		}
+		if n < r.batchSize {
+			return nil
+		}
+	}
+	return nil
+}
+
+// publishBatch claims up to a batch of events, publishes them and records the
+// result of each. It returns the number of claimed events.
+func (r *Relay) publishBatch(ctx context.Context) (int, error) {
+	const op = "kafka.outbox.Relay.publishBatch()"
+
+	events, err := r.repo.ClaimOutbox(ctx, r.batchSize, r.lease)
+	if err != nil {
+		return 0, e.Wrap(op, err)
+	}
+	if len(events) == 0 {
+		return 0, nil
+	}
+
+	messages := make([]kafka.Message, 0, len(events))
+	for _, event := range events {
+		messages = append(messages, Message(event))
+	}
+
+	writeErr := r.writer.WriteMessages(ctx, messages...)
+
+	// Kafka may have accepted the events even if ctx was cancelled meanwhile,
+	// record what is known so they are not published twice.
+	ctx = context.WithoutCancel(ctx)
+
+	var writeErrs kafka.WriteErrors
+	perMessage := errors.As(writeErr, &writeErrs) && len(writeErrs) == len(events)
+
+	sent := make([]int64, 0, len(events))
+	failed := 0
+	for i, event := range events {
+		err := writeErr
+		if perMessage {
+			err = writeErrs[i]
+		}
+		if err == nil {
+			sent = append(sent, event.ID)
+			continue
+		}
+
+		failed++
+		delay := r.retryPolicy.Backoff(event.Attempts + 1)
+		if err := r.repo.RetryOutbox(ctx, event.ID, err.Error(), delay); err != nil {
+			return 0, e.Wrap(op, err)
+		}
+	}
+	metrics.OutboxPublishFailures.Add(float64(failed))
+
+	if err := r.repo.MarkOutboxSent(ctx, sent); err != nil {
+		return 0, e.Wrap(op, err)
+	}
+	metrics.OutboxEventsPublished.Add(float64(len(sent)))
+
+	if writeErr != nil {
+		return len(events), e.Wrap(op, writeErr)
+	}
+	return len(events), nil
+}
+
+// Message builds the Kafka message of event, keyed by its order uid.
+func Message(event domain.OutboxEvent) kafka.Message {
+	return kafka.Message{
+		Key:   []byte(event.AggregateID.String()),
+		Value: event.Payload,
+		Time:  event.CreatedAt,
+		Headers: []kafka.Header{
+			{Key: HeaderEventID, Value: []byte(strconv.FormatInt(event.ID, 10))},
+			{Key: HeaderEventType, Value: []byte(event.Type)},
+		},
+	}
+}
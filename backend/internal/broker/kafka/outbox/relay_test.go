@@ -0,0 +1,200 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/ilam072/wbtech-l0/backend/internal/config"
+	"github.com/ilam072/wbtech-l0/backend/internal/types/domain"
+	mocks "github.com/ilam072/wbtech-l0/backend/mocks/outbox"
+	"github.com/ilam072/wbtech-l0/backend/pkg/logger/handlers/slogdiscard"
+	"github.com/segmentio/kafka-go"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"testing"
+	"time"
+)
+
+var testConfig = config.OutboxConfig{
+	PollInterval:        10 * time.Millisecond,
+	BatchSize:           2,
+	Lease:               time.Second,
+	RetryInitialBackoff: time.Second,
+	RetryMaxBackoff:     time.Minute,
+}
+
+func testEvents(n int) []domain.OutboxEvent {
+	events := make([]domain.OutboxEvent, 0, n)
+	for i := 1; i <= n; i++ {
+		events = append(events, domain.OutboxEvent{
+			ID:          int64(i),
+			AggregateID: uuid.New(),
+			Type:        domain.EventOrderCreated,
+			Payload:     []byte(`{"order_uid":"x"}`),
+			CreatedAt:   time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC),
+		})
+	}
+	return events
+}
+
+func TestMessage(t *testing.T) {
+	event := testEvents(1)[0]
+
+	msg := Message(event)
+	assert.Equal(t, event.AggregateID.String(), string(msg.Key))
+	assert.Equal(t, event.Payload, msg.Value)
+	assert.Equal(t, event.CreatedAt, msg.Time)
+	assert.Equal(t, []kafka.Header{
+		{Key: HeaderEventID, Value: []byte("1")},
+		{Key: HeaderEventType, Value: []byte("order.created")},
+	}, msg.Headers)
+}
+
+func TestRelay_PublishPending(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockRepo(ctrl)
+	writer := mocks.NewMockWriter(ctrl)
+	r := New(slogdiscard.NewDiscardLogger(), repo, writer, testConfig)
+
+	events := testEvents(3)
+
+	gomock.InOrder(
+		repo.EXPECT().ClaimOutbox(gomock.Any(), 2, time.Second).Return(events[:2], nil),
+		writer.EXPECT().WriteMessages(gomock.Any(), Message(events[0]), Message(events[1])).Return(nil),
+		repo.EXPECT().MarkOutboxSent(gomock.Any(), []int64{1, 2}).Return(nil),
+		// a full batch means more events may be ready
+		repo.EXPECT().ClaimOutbox(gomock.Any(), 2, time.Second).Return(events[2:], nil),
+		writer.EXPECT().WriteMessages(gomock.Any(), Message(events[2])).Return(nil),
+		repo.EXPECT().MarkOutboxSent(gomock.Any(), []int64{3}).Return(nil),
+	)
+
+	assert.NoError(t, r.publishPending(context.Background()))
+}
+
+func TestRelay_PublishPending_WriteFails(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockRepo(ctrl)
+	writer := mocks.NewMockWriter(ctrl)
+	r := New(slogdiscard.NewDiscardLogger(), repo, writer, testConfig)
+
+	events := testEvents(2)
+	events[1].Attempts = 3
+	writeErr := errors.New("kafka is down")
+
+	gomock.InOrder(
+		repo.EXPECT().ClaimOutbox(gomock.Any(), 2, time.Second).Return(events, nil),
+		writer.EXPECT().WriteMessages(gomock.Any(), gomock.Any()).Return(writeErr),
+		repo.EXPECT().RetryOutbox(gomock.Any(), int64(1), "kafka is down", gomock.Any()).DoAndReturn(
+			func(_ context.Context, _ int64, _ string, delay time.Duration) error {
+				assert.InDelta(t, 750*time.Millisecond, delay, float64(250*time.Millisecond))
+				return nil
+			}),
+		repo.EXPECT().RetryOutbox(gomock.Any(), int64(2), "kafka is down", gomock.Any()).DoAndReturn(
+			func(_ context.Context, _ int64, _ string, delay time.Duration) error {
+				assert.InDelta(t, 6*time.Second, delay, float64(2*time.Second))
+				return nil
+			}),
+		repo.EXPECT().MarkOutboxSent(gomock.Any(), []int64{}).Return(nil),
+	)
+
+	// the failed batch stops publishing until the next poll
+	err := r.publishPending(context.Background())
+	assert.ErrorIs(t, err, writeErr)
+}
+
+func TestRelay_PublishPending_PartialFailure(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockRepo(ctrl)
+	writer := mocks.NewMockWriter(ctrl)
+	r := New(slogdiscard.NewDiscardLogger(), repo, writer, testConfig)
+
+	events := testEvents(2)
+
+	gomock.InOrder(
+		repo.EXPECT().ClaimOutbox(gomock.Any(), 2, time.Second).Return(events, nil),
+		writer.EXPECT().WriteMessages(gomock.Any(), gomock.Any()).
+			Return(kafka.WriteErrors{nil, kafka.LeaderNotAvailable}),
+		repo.EXPECT().RetryOutbox(gomock.Any(), int64(2), kafka.LeaderNotAvailable.Error(), gomock.Any()).Return(nil),
+		repo.EXPECT().MarkOutboxSent(gomock.Any(), []int64{1}).Return(nil),
+	)
+
+	err := r.publishPending(context.Background())
+	assert.Error(t, err)
+}
+
+func TestRelay_Start_StopsOnCancel(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockRepo(ctrl)
+	writer := mocks.NewMockWriter(ctrl)
+	r := New(slogdiscard.NewDiscardLogger(), repo, writer, testConfig)
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	polls := 0
+	repo.EXPECT().ClaimOutbox(gomock.Any(), 2, time.Second).DoAndReturn(
+		func(context.Context, int, time.Duration) ([]domain.OutboxEvent, error) {
+			polls++
+			if polls == 3 {
+				cancel()
+			}
+			return nil, nil
+		}).MinTimes(3)
+
+	done := make(chan struct{})
+	go func() {
+		r.Start(ctx)
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("relay did not stop")
+	}
+}
+
+func TestRelay_Start_PrunesEveryPruneInterval(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockRepo(ctrl)
+	writer := mocks.NewMockWriter(ctrl)
+	cfg := testConfig
+	cfg.Retention = 24 * time.Hour
+	cfg.PruneInterval = time.Hour
+	r := New(slogdiscard.NewDiscardLogger(), repo, writer, cfg)
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	polls := 0
+	repo.EXPECT().ClaimOutbox(gomock.Any(), 2, time.Second).DoAndReturn(
+		func(context.Context, int, time.Duration) ([]domain.OutboxEvent, error) {
+			polls++
+			if polls == 3 {
+				cancel()
+			}
+			return nil, nil
+		}).MinTimes(3)
+	// the first poll prunes, the following ones are within the prune interval
+	repo.EXPECT().PruneOutbox(gomock.Any(), 24*time.Hour).Return(int64(5), nil).Times(1)
+
+	done := make(chan struct{})
+	go func() {
+		r.Start(ctx)
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("relay did not stop")
+	}
+}
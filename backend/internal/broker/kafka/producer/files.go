@@ -0,0 +1,75 @@
+package producer
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/ilam072/wbtech-l0/backend/pkg/e"
+	"github.com/segmentio/kafka-go"
+	"os"
+	"path/filepath"
+)
+
+// FileMessages reads messages from JSON files. A directory stands for the
+// *.json files inside it in name order. A file holding an array yields one
+// message per element. Files that are not valid JSON are published as is,
+// which allows sending malformed fixtures.
+func FileMessages(paths ...string) ([]kafka.Message, error) {
+	const op = "producer.FileMessages()"
+
+	var msgs []kafka.Message
+	for _, path := range paths {
+		files, err := expand(path)
+		if err != nil {
+			return nil, e.Wrap(op, err)
+		}
+
+		for _, file := range files {
+			data, err := os.ReadFile(file)
+			if err != nil {
+				return nil, e.Wrap(op, err)
+			}
+			msgs = append(msgs, payloadMessages(data)...)
+		}
+	}
+	return msgs, nil
+}
+
+func expand(path string) ([]string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return []string{path}, nil
+	}
+	return filepath.Glob(filepath.Join(path, "*.json"))
+}
+
+func payloadMessages(data []byte) []kafka.Message {
+	data = bytes.TrimSpace(data)
+
+	var values []json.RawMessage
+	if err := json.Unmarshal(data, &values); err != nil {
+		values = []json.RawMessage{data}
+	}
+
+	msgs := make([]kafka.Message, 0, len(values))
+	for _, value := range values {
+		var compacted bytes.Buffer
+		if err := json.Compact(&compacted, value); err == nil {
+			value = compacted.Bytes()
+		}
+
+		var order struct {
+			OrderUID string `json:"order_uid"`
+		}
+		_ = json.Unmarshal(value, &order)
+
+		msg := kafka.Message{Value: value}
+		if order.OrderUID != "" {
+			msg.Key = []byte(order.OrderUID)
+		}
+		msgs = append(msgs, msg)
+	}
+	return msgs
+}
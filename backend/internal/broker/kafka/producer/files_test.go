@@ -0,0 +1,42 @@
+package producer
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFileMessages(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+		return path
+	}
+
+	write("a.json", "{\n  \"order_uid\": \"a\",\n  \"track_number\": \"WB1\"\n}\n")
+	write("b.json", `[{"order_uid": "b"}, {"track_number": "WB3"}]`)
+	write("notes.txt", "ignored")
+	broken := write("broken.txt", `{"order_uid": "c"`)
+
+	msgs, err := FileMessages(dir, broken)
+	require.NoError(t, err)
+	require.Len(t, msgs, 4)
+
+	assert.Equal(t, "a", string(msgs[0].Key))
+	assert.Equal(t, `{"order_uid":"a","track_number":"WB1"}`, string(msgs[0].Value))
+	assert.Equal(t, "b", string(msgs[1].Key))
+	assert.Equal(t, `{"order_uid":"b"}`, string(msgs[1].Value))
+	assert.Nil(t, msgs[2].Key)
+	assert.Equal(t, `{"track_number":"WB3"}`, string(msgs[2].Value))
+	assert.Nil(t, msgs[3].Key)
+	assert.Equal(t, `{"order_uid": "c"`, string(msgs[3].Value))
+}
+
+func TestFileMessages_Missing(t *testing.T) {
+	_, err := FileMessages(filepath.Join(t.TempDir(), "missing.json"))
+
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
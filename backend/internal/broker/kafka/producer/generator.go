@@ -0,0 +1,193 @@
+package producer
+
+import (
+	"encoding/binary"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/ilam072/wbtech-l0/backend/internal/types/dto"
+	"math/rand/v2"
+	"strings"
+	"time"
+)
+
+var (
+	firstNames = []string{"Ivan", "Anna", "Pavel", "Olga", "Dmitry", "Maria", "Sergey", "Elena", "Alexey", "Irina"}
+	lastNames  = []string{"Ivanov", "Petrova", "Smirnov", "Kuznetsova", "Popov", "Sokolova", "Lebedev", "Novikova"}
+	cities     = []struct{ city, region string }{
+		{"Moscow", "Moscow"},
+		{"Saint Petersburg", "Saint Petersburg"},
+		{"Kazan", "Tatarstan"},
+		{"Yekaterinburg", "Sverdlovsk Oblast"},
+		{"Novosibirsk", "Novosibirsk Oblast"},
+	}
+	streets          = []string{"Lenina", "Pushkina", "Gagarina", "Mira", "Sadovaya", "Ploshad Mira"}
+	deliveryServices = []string{"meest", "cdek", "boxberry", "wb-courier"}
+	currencies       = []string{"RUB", "USD", "EUR"}
+	banks            = []string{"alpha", "sber", "tinkoff", "vtb"}
+	locales          = []string{"ru", "en"}
+	sizes            = []string{"0", "XS", "S", "M", "L", "XL"}
+	products         = []struct{ name, brand string }{
+		{"Mascaras", "Vivienne Sabo"},
+		{"T-shirt", "Gloria Jeans"},
+		{"Sneakers", "Demix"},
+		{"Backpack", "Xiaomi"},
+		{"Headphones", "JBL"},
+		{"Phone case", "Baseus"},
+		{"Notebook", "Hatber"},
+		{"Thermos", "Stanley"},
+	}
+	invalidations = []func(*dto.Order){
+		func(o *dto.Order) { o.OrderUID = "not-a-uuid" },
+		func(o *dto.Order) { o.TrackNumber = "" },
+		func(o *dto.Order) { o.Delivery.Email = strings.Replace(o.Delivery.Email, "@", "", 1) },
+		func(o *dto.Order) { o.Items = nil },
+		func(o *dto.Order) { o.Items[0].TotalPrice += 100 },
+		func(o *dto.Order) { o.Payment.GoodsTotal += 100 },
+		func(o *dto.Order) { o.Payment.Amount += 100 },
+	}
+)
+
+const (
+	customers = 50
+	maxItems  = 5
+	maxAge    = 30 * 24 * time.Hour
+
+	// chrt_id is unique across all orders, so every generator hands out
+	// consecutive IDs from a random start. The range is far above real IDs
+	// and wide enough for runs with different seeds not to overlap, while
+	// staying exact in JavaScript numbers.
+	minChrtID = 1_000_000_000
+	maxChrtID = 1 << 53
+)
+
+// Generator builds random orders passing the struct tags and the business
+// rules of the validator. The same seed and now produce the same orders.
+type Generator struct {
+	src          *rand.ChaCha8
+	rng          *rand.Rand
+	invalidRatio float64
+	now          time.Time
+	chrtID       int
+}
+
+// NewGenerator returns a generator breaking one field or monetary rule in
+// roughly invalidRatio of the orders. Orders are created within a month
+// before now.
+func NewGenerator(seed uint64, invalidRatio float64, now time.Time) *Generator {
+	var key [32]byte
+	binary.LittleEndian.PutUint64(key[:], seed)
+
+	src := rand.NewChaCha8(key)
+	rng := rand.New(src)
+	return &Generator{
+		src:          src,
+		rng:          rng,
+		invalidRatio: invalidRatio,
+		now:          now.UTC(),
+		chrtID:       minChrtID + rng.IntN(maxChrtID-minChrtID),
+	}
+}
+
+// Next returns the next order and whether it is valid.
+func (g *Generator) Next() (dto.Order, bool) {
+	order := g.order()
+
+	if g.rng.Float64() >= g.invalidRatio {
+		return order, true
+	}
+	invalidations[g.rng.IntN(len(invalidations))](&order)
+	return order, false
+}
+
+func (g *Generator) order() dto.Order {
+	orderUID := g.uuid()
+	trackNumber := "WB" + g.code(10)
+	created := g.now.Add(-time.Duration(g.rng.Int64N(int64(maxAge)))).Truncate(time.Second)
+
+	items := make([]dto.Item, 1+g.rng.IntN(maxItems))
+	goodsTotal := 0
+	for i := range items {
+		product := pick(g.rng, products)
+		price := 100 + g.rng.IntN(9_900)
+		sale := 1 + g.rng.IntN(90)
+
+		items[i] = dto.Item{
+			ChrtID:      g.chrtID,
+			TrackNumber: trackNumber,
+			Price:       price,
+			Rid:         strings.ReplaceAll(g.uuid(), "-", ""),
+			Name:        product.name,
+			Sale:        sale,
+			Size:        pick(g.rng, sizes),
+			TotalPrice:  price * (100 - sale) / 100,
+			NmID:        1_000_000 + g.rng.IntN(9_000_000),
+			Brand:       product.brand,
+			Status:      202,
+		}
+		goodsTotal += items[i].TotalPrice
+		g.chrtID++
+	}
+
+	firstName, lastName := pick(g.rng, firstNames), pick(g.rng, lastNames)
+	city := pick(g.rng, cities)
+	deliveryCost := 100 + g.rng.IntN(1_400)
+	customFee := 1 + g.rng.IntN(200)
+
+	return dto.Order{
+		OrderUID:    orderUID,
+		TrackNumber: trackNumber,
+		Entry:       "WBIL",
+		Delivery: dto.Delivery{
+			Name:    firstName + " " + lastName,
+			Phone:   fmt.Sprintf("+79%09d", g.rng.IntN(1_000_000_000)),
+			Zip:     fmt.Sprintf("%06d", 100_000+g.rng.IntN(900_000)),
+			City:    city.city,
+			Address: fmt.Sprintf("%s %d", pick(g.rng, streets), 1+g.rng.IntN(150)),
+			Region:  city.region,
+			Email:   strings.ToLower(firstName+"."+lastName) + "@example.com",
+		},
+		Payment: dto.Payment{
+			Transaction:  orderUID,
+			Currency:     pick(g.rng, currencies),
+			Provider:     "wbpay",
+			Amount:       goodsTotal + deliveryCost + customFee,
+			PaymentDt:    created.Unix(),
+			Bank:         pick(g.rng, banks),
+			DeliveryCost: deliveryCost,
+			GoodsTotal:   goodsTotal,
+			CustomFee:    customFee,
+		},
+		Items:           items,
+		Locale:          pick(g.rng, locales),
+		CustomerID:      fmt.Sprintf("customer-%03d", g.rng.IntN(customers)),
+		DeliveryService: pick(g.rng, deliveryServices),
+		Shardkey:        fmt.Sprint(1 + g.rng.IntN(9)),
+		SmID:            1 + g.rng.IntN(100),
+		DateCreated:     created,
+		OofShard:        fmt.Sprint(1 + g.rng.IntN(2)),
+	}
+}
+
+func (g *Generator) uuid() string {
+	id, err := uuid.NewRandomFromReader(g.src)
+	if err != nil {
+		// ChaCha8 reads never fail.
+		panic(err)
+	}
+	return id.String()
+}
+
+// code returns n random upper case letters and digits.
+func (g *Generator) code(n int) string {
+	const alphabet = "ABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+
+	b := make([]byte, n)
+	for i := range b {
+		b[i] = alphabet[g.rng.IntN(len(alphabet))]
+	}
+	return string(b)
+}
+
+func pick[T any](rng *rand.Rand, values []T) T {
+	return values[rng.IntN(len(values))]
+}
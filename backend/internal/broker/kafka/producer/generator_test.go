@@ -0,0 +1,78 @@
+package producer
+
+import (
+	"github.com/ilam072/wbtech-l0/backend/internal/config"
+	"github.com/ilam072/wbtech-l0/backend/internal/validator"
+	"github.com/ilam072/wbtech-l0/backend/pkg/logger/handlers/slogdiscard"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+var now = time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+
+func TestGenerator_Valid(t *testing.T) {
+	v := validator.New(slogdiscard.NewDiscardLogger(), config.ValidationConfig{})
+	g := NewGenerator(42, 0, now)
+
+	for range 500 {
+		order, valid := g.Next()
+
+		assert.True(t, valid)
+		require.NoError(t, v.Validate(order))
+		assert.False(t, order.DateCreated.After(now))
+	}
+}
+
+func TestGenerator_Invalid(t *testing.T) {
+	v := validator.New(slogdiscard.NewDiscardLogger(), config.ValidationConfig{Tolerance: 1})
+	g := NewGenerator(42, 1, now)
+
+	for range 500 {
+		order, valid := g.Next()
+
+		assert.False(t, valid)
+		assert.Error(t, v.Validate(order))
+	}
+}
+
+func TestGenerator_Seed(t *testing.T) {
+	a, b := NewGenerator(7, 0.3, now), NewGenerator(7, 0.3, now)
+	other := NewGenerator(8, 0.3, now)
+
+	for range 50 {
+		orderA, validA := a.Next()
+		orderB, validB := b.Next()
+		orderOther, _ := other.Next()
+
+		assert.Equal(t, orderA, orderB)
+		assert.Equal(t, validA, validB)
+		assert.NotEqual(t, orderA.OrderUID, orderOther.OrderUID)
+	}
+}
+
+func TestGenerator_UniqueChrtIDs(t *testing.T) {
+	seen := make(map[int]bool)
+	for _, seed := range []uint64{1, 2} {
+		g := NewGenerator(seed, 0, now)
+
+		for range 10_000 {
+			order, _ := g.Next()
+			for _, item := range order.Items {
+				require.False(t, seen[item.ChrtID], "chrt_id %d generated twice", item.ChrtID)
+				seen[item.ChrtID] = true
+			}
+		}
+	}
+}
+
+func TestOrderMessage(t *testing.T) {
+	order, _ := NewGenerator(1, 0, now).Next()
+
+	msg, err := OrderMessage(order)
+
+	require.NoError(t, err)
+	assert.Equal(t, order.OrderUID, string(msg.Key))
+	assert.Contains(t, string(msg.Value), `"order_uid":"`+order.OrderUID+`"`)
+}
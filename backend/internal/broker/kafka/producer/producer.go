@@ -0,0 +1,73 @@
+package producer
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/ilam072/wbtech-l0/backend/internal/types/dto"
+	"github.com/ilam072/wbtech-l0/backend/pkg/e"
+	"github.com/segmentio/kafka-go"
+	"time"
+)
+
+type Producer struct {
+	w *kafka.Writer
+}
+
+func New(topic string, addr ...string) *Producer {
+	w := &kafka.Writer{
+		Addr:                   kafka.TCP(addr...),
+		Topic:                  topic,
+		Balancer:               &kafka.Hash{},
+		RequiredAcks:           kafka.RequireAll,
+		AllowAutoTopicCreation: true,
+		// Callers pass whole batches, so there is no point in waiting
+		// for more messages before a write.
+		BatchTimeout: 10 * time.Millisecond,
+	}
+	return &Producer{w: w}
+}
+
+// Publish writes msgs in a single batch.
+func (p *Producer) Publish(ctx context.Context, msgs ...kafka.Message) error {
+	const op = "producer.Publish()"
+
+	if err := p.w.WriteMessages(ctx, msgs...); err != nil {
+		return e.Wrap(op, err)
+	}
+	return nil
+}
+
+// PublishOrders writes orders as JSON keyed by order_uid.
+func (p *Producer) PublishOrders(ctx context.Context, orders ...dto.Order) error {
+	const op = "producer.PublishOrders()"
+
+	msgs := make([]kafka.Message, 0, len(orders))
+	for _, order := range orders {
+		msg, err := OrderMessage(order)
+		if err != nil {
+			return e.Wrap(op, err)
+		}
+		msgs = append(msgs, msg)
+	}
+
+	if err := p.Publish(ctx, msgs...); err != nil {
+		return e.Wrap(op, err)
+	}
+	return nil
+}
+
+func (p *Producer) Close() error {
+	return p.w.Close()
+}
+
+// OrderMessage builds the message the orders consumer expects. Keying by
+// order_uid keeps revisions of one order in the same partition.
+func OrderMessage(order dto.Order) (kafka.Message, error) {
+	const op = "producer.OrderMessage()"
+
+	value, err := json.Marshal(order)
+	if err != nil {
+		return kafka.Message{}, e.Wrap(op, err)
+	}
+	return kafka.Message{Key: []byte(order.OrderUID), Value: value}, nil
+}
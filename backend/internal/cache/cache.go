@@ -2,14 +2,23 @@ package cache
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"github.com/ilam072/wbtech-l0/backend/internal/config"
+	"github.com/ilam072/wbtech-l0/backend/internal/repo"
 	"github.com/ilam072/wbtech-l0/backend/internal/types/domain"
 	"github.com/ilam072/wbtech-l0/backend/internal/types/dto"
 	"github.com/ilam072/wbtech-l0/backend/pkg/e"
 	"github.com/maypok86/otter/v2"
+	"github.com/maypok86/otter/v2/stats"
+	"math"
+	"sync/atomic"
 )
 
 type OrderRepo interface {
+	GetOrder(ctx context.Context, ID string) (domain.FullOrder, error)
 	GetLastOrders(ctx context.Context, limit int) ([]domain.FullOrder, error)
+	GetWatermark(ctx context.Context) (domain.Watermark, error)
 }
 
 type OrderConverter interface {
@@ -19,26 +28,64 @@ type OrderConverter interface {
 
 type OrderCache struct {
 	store     *otter.Cache[string, dto.Order]
+	tracks    *otter.Cache[string, string] // track number -> order uid
+	missing   *otter.Cache[string, struct{}]
 	orderRepo OrderRepo
 	converter OrderConverter
+
+	savedQueries atomic.Uint64
 }
 
-func New(orderRepo OrderRepo, converter OrderConverter) *OrderCache {
+func New(orderRepo OrderRepo, converter OrderConverter, cfg config.CacheConfig) *OrderCache {
+	c := &OrderCache{
+		tracks:    newTrackIndex(cfg),
+		orderRepo: orderRepo,
+		converter: converter,
+	}
+
 	opts := &otter.Options[string, dto.Order]{
-		MaximumSize: 1000,
+		OnDeletion:    c.dropTrack,
+		StatsRecorder: stats.NewCounter(),
 	}
 
-	cache := otter.Must[string, dto.Order](opts)
+	if cfg.MaxWeight > 0 {
+		opts.MaximumWeight = cfg.MaxWeight
+		opts.Weigher = weigh
+	} else {
+		opts.MaximumSize = cfg.MaxSize
+	}
 
-	return &OrderCache{
-		store:     cache,
-		orderRepo: orderRepo,
-		converter: converter,
+	switch {
+	case cfg.ExpireAfterAccess > 0:
+		opts.ExpiryCalculator = otter.ExpiryAccessing[string, dto.Order](cfg.ExpireAfterAccess)
+	case cfg.ExpireAfterWrite > 0:
+		opts.ExpiryCalculator = otter.ExpiryWriting[string, dto.Order](cfg.ExpireAfterWrite)
+	}
+
+	if cfg.RefreshAfterWrite > 0 {
+		opts.RefreshCalculator = otter.RefreshWriting[string, dto.Order](cfg.RefreshAfterWrite)
 	}
+
+	c.store = otter.Must[string, dto.Order](opts)
+
+	if cfg.NegativeTTL > 0 {
+		c.missing = otter.Must[string, struct{}](&otter.Options[string, struct{}]{
+			MaximumSize:      cfg.NegativeMaxSize,
+			ExpiryCalculator: otter.ExpiryWriting[string, struct{}](cfg.NegativeTTL),
+		})
+	}
+
+	return c
 }
 
 func (c *OrderCache) Set(key string, order dto.Order) {
+	if c.missing != nil {
+		c.missing.Invalidate(key)
+	}
 	c.store.Set(key, order)
+	if order.TrackNumber != "" {
+		c.tracks.Set(order.TrackNumber, key)
+	}
 }
 
 func (c *OrderCache) Get(key string) (dto.Order, bool) {
@@ -47,6 +94,99 @@ func (c *OrderCache) Get(key string) (dto.Order, bool) {
 	return order, ok
 }
 
+// Load returns the cached order or reads it from the repository and caches
+// it. Concurrent loads of the same key share a single repository query.
+// Stale entries are served while being refreshed in the background, and
+// keys recently found missing are rejected without a query.
+func (c *OrderCache) Load(ctx context.Context, key string) (dto.Order, error) {
+	const op = "cache.Load()"
+
+	if c.missing != nil {
+		if _, ok := c.missing.GetIfPresent(key); ok {
+			c.savedQueries.Add(1)
+			return dto.Order{}, e.Wrap(op, repo.ErrOrderNotFound)
+		}
+	}
+
+	order, err := c.store.Get(ctx, key, loader{c})
+	if err != nil {
+		if errors.Is(err, otter.ErrNotFound) {
+			if c.missing != nil {
+				c.missing.Set(key, struct{}{})
+			}
+			return dto.Order{}, e.Wrap(op, repo.ErrOrderNotFound)
+		}
+		return dto.Order{}, e.Wrap(op, err)
+	}
+	return order, nil
+}
+
+func (c *OrderCache) GetByTrackNumber(trackNumber string) (dto.Order, bool) {
+	key, ok := c.tracks.GetIfPresent(trackNumber)
+	if !ok {
+		return dto.Order{}, false
+	}
+
+	order, ok := c.store.GetIfPresent(key)
+	if !ok || order.TrackNumber != trackNumber {
+		// the order was evicted or its track number changed
+		c.tracks.Invalidate(trackNumber)
+		return dto.Order{}, false
+	}
+
+	return order, true
+}
+
+// Invalidate evicts a single order and reports whether it was cached. A
+// negative entry for the key is dropped as well.
+func (c *OrderCache) Invalidate(key string) bool {
+	if c.missing != nil {
+		c.missing.Invalidate(key)
+	}
+
+	order, ok := c.store.Invalidate(key)
+	if ok && order.TrackNumber != "" {
+		if uid, found := c.tracks.GetIfPresent(order.TrackNumber); found && uid == key {
+			c.tracks.Invalidate(order.TrackNumber)
+		}
+	}
+	return ok
+}
+
+// Purge evicts every cached order, including negative entries.
+func (c *OrderCache) Purge() {
+	c.store.InvalidateAll()
+	c.tracks.InvalidateAll()
+	if c.missing != nil {
+		c.missing.InvalidateAll()
+	}
+}
+
+func (c *OrderCache) Stats() dto.CacheStats {
+	s := c.store.Stats()
+
+	cs := dto.CacheStats{
+		Size:          c.store.EstimatedSize(),
+		Hits:          s.Hits,
+		Misses:        s.Misses,
+		HitRatio:      s.HitRatio(),
+		Evictions:     s.Evictions,
+		LoadSuccesses: s.LoadSuccesses,
+		LoadFailures:  s.LoadFailures,
+		SavedQueries:  c.SavedQueries(),
+	}
+	if c.missing != nil {
+		cs.MissingSize = c.missing.EstimatedSize()
+	}
+	return cs
+}
+
+// SavedQueries returns how many loads were answered from the negative cache
+// instead of querying the repository.
+func (c *OrderCache) SavedQueries() uint64 {
+	return c.savedQueries.Load()
+}
+
 func (c *OrderCache) Preload(ctx context.Context, limit int) error {
 	const op = "cache.Preload()"
 
@@ -65,3 +205,74 @@ func (c *OrderCache) Preload(ctx context.Context, limit int) error {
 	}
 	return nil
 }
+
+// newTrackIndex returns a track number index bounded and expiring like the
+// order store. dropTrack only follows evictions, so entries of orders whose
+// track number changed would otherwise stay until they are looked up.
+func newTrackIndex(cfg config.CacheConfig) *otter.Cache[string, string] {
+	opts := &otter.Options[string, string]{}
+
+	if cfg.MaxWeight > 0 {
+		opts.MaximumWeight = cfg.MaxWeight
+		opts.Weigher = func(trackNumber, key string) uint32 {
+			return uint32(len(trackNumber) + len(key))
+		}
+	} else {
+		opts.MaximumSize = cfg.MaxSize
+	}
+
+	switch {
+	case cfg.ExpireAfterAccess > 0:
+		opts.ExpiryCalculator = otter.ExpiryAccessing[string, string](cfg.ExpireAfterAccess)
+	case cfg.ExpireAfterWrite > 0:
+		opts.ExpiryCalculator = otter.ExpiryWriting[string, string](cfg.ExpireAfterWrite)
+	}
+
+	return otter.Must[string, string](opts)
+}
+
+// dropTrack keeps the track number index from outliving evicted orders.
+func (c *OrderCache) dropTrack(event otter.DeletionEvent[string, dto.Order]) {
+	if !event.WasEvicted() || event.Value.TrackNumber == "" {
+		return
+	}
+	if key, ok := c.tracks.GetIfPresent(event.Value.TrackNumber); ok && key == event.Key {
+		c.tracks.Invalidate(event.Value.TrackNumber)
+	}
+}
+
+// weigh measures an order by the size of its JSON representation.
+func weigh(_ string, order dto.Order) uint32 {
+	b, err := json.Marshal(order)
+	if err != nil || len(b) > math.MaxUint32 {
+		return math.MaxUint32
+	}
+	return uint32(len(b))
+}
+
+// loader reads orders from the repository on misses and refreshes.
+type loader struct {
+	c *OrderCache
+}
+
+func (l loader) Load(ctx context.Context, key string) (dto.Order, error) {
+	const op = "cache.loader.Load()"
+
+	fullOrder, err := l.c.orderRepo.GetOrder(ctx, key)
+	if err != nil {
+		if errors.Is(err, repo.ErrOrderNotFound) {
+			return dto.Order{}, otter.ErrNotFound
+		}
+		return dto.Order{}, e.Wrap(op, err)
+	}
+
+	order := l.c.converter.DomainToDtoOrder(fullOrder)
+	if order.TrackNumber != "" {
+		l.c.tracks.Set(order.TrackNumber, key)
+	}
+	return order, nil
+}
+
+func (l loader) Reload(ctx context.Context, key string, _ dto.Order) (dto.Order, error) {
+	return l.Load(ctx, key)
+}
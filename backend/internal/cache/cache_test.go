@@ -0,0 +1,300 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/ilam072/wbtech-l0/backend/internal/config"
+	"github.com/ilam072/wbtech-l0/backend/internal/converter"
+	"github.com/ilam072/wbtech-l0/backend/internal/repo"
+	"github.com/ilam072/wbtech-l0/backend/internal/types/domain"
+	"github.com/ilam072/wbtech-l0/backend/internal/types/dto"
+	"github.com/stretchr/testify/assert"
+	"sync"
+	"testing"
+	"time"
+)
+
+type fakeRepo struct {
+	mu      sync.Mutex
+	orders  map[string]domain.FullOrder
+	calls   int
+	release chan struct{} // when set, GetOrder blocks until it is closed
+	mark    domain.Watermark
+}
+
+func (r *fakeRepo) GetOrder(_ context.Context, ID string) (domain.FullOrder, error) {
+	if r.release != nil {
+		<-r.release
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.calls++
+	order, ok := r.orders[ID]
+	if !ok {
+		return domain.FullOrder{}, repo.ErrOrderNotFound
+	}
+	return order, nil
+}
+
+func (r *fakeRepo) GetLastOrders(context.Context, int) ([]domain.FullOrder, error) {
+	return nil, nil
+}
+
+func (r *fakeRepo) GetWatermark(context.Context) (domain.Watermark, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.mark, nil
+}
+
+func (r *fakeRepo) setOrder(order domain.FullOrder) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.orders[order.Order.ID.String()] = order
+}
+
+func TestOrderCache_GetByTrackNumber(t *testing.T) {
+	c := New(nil, converter.New(), config.CacheConfig{MaxSize: 100})
+
+	order := dto.Order{OrderUID: uuid.New().String(), TrackNumber: "WBILMTESTTRACK"}
+	c.Set(order.OrderUID, order)
+
+	cached, ok := c.GetByTrackNumber("WBILMTESTTRACK")
+	assert.True(t, ok)
+	assert.Equal(t, order, cached)
+
+	_, ok = c.GetByTrackNumber("UNKNOWN")
+	assert.False(t, ok)
+}
+
+func TestOrderCache_GetByTrackNumber_StaleIndex(t *testing.T) {
+	c := New(nil, converter.New(), config.CacheConfig{MaxSize: 100})
+
+	order := dto.Order{OrderUID: uuid.New().String(), TrackNumber: "OLDTRACK"}
+	c.Set(order.OrderUID, order)
+
+	order.TrackNumber = "NEWTRACK"
+	c.Set(order.OrderUID, order)
+
+	_, ok := c.GetByTrackNumber("OLDTRACK")
+	assert.False(t, ok)
+
+	cached, ok := c.GetByTrackNumber("NEWTRACK")
+	assert.True(t, ok)
+	assert.Equal(t, order, cached)
+}
+
+func TestOrderCache_TrackIndexBounded(t *testing.T) {
+	c := New(nil, converter.New(), config.CacheConfig{MaxSize: 10})
+
+	order := dto.Order{OrderUID: uuid.New().String()}
+	for i := range 1000 {
+		order.TrackNumber = fmt.Sprintf("TRACK%04d", i)
+		c.Set(order.OrderUID, order)
+	}
+	c.tracks.CleanUp()
+
+	assert.LessOrEqual(t, c.tracks.EstimatedSize(), 10)
+}
+
+func TestOrderCache_ExpireAfterWrite(t *testing.T) {
+	c := New(nil, converter.New(), config.CacheConfig{MaxSize: 100, ExpireAfterWrite: 20 * time.Millisecond})
+
+	order := dto.Order{OrderUID: uuid.New().String()}
+	c.Set(order.OrderUID, order)
+
+	_, ok := c.Get(order.OrderUID)
+	assert.True(t, ok)
+
+	assert.Eventually(t, func() bool {
+		_, ok := c.Get(order.OrderUID)
+		return !ok
+	}, time.Second, 5*time.Millisecond)
+}
+
+func TestOrderCache_MaxWeight(t *testing.T) {
+	order := dto.Order{OrderUID: uuid.New().String()}
+	c := New(nil, converter.New(), config.CacheConfig{MaxWeight: uint64(weigh("", order)) * 3})
+
+	for i := 0; i < 50; i++ {
+		o := dto.Order{OrderUID: uuid.New().String()}
+		c.Set(o.OrderUID, o)
+	}
+
+	c.store.CleanUp()
+	assert.LessOrEqual(t, c.store.EstimatedSize(), 3)
+}
+
+func TestOrderCache_RefreshAfterWrite(t *testing.T) {
+	conv := converter.New()
+	id := uuid.New()
+	fullOrder := domain.FullOrder{Order: domain.Order{ID: id, TrackNumber: "OLDTRACK"}}
+	orderRepo := &fakeRepo{orders: map[string]domain.FullOrder{}}
+
+	c := New(orderRepo, conv, config.CacheConfig{MaxSize: 100, RefreshAfterWrite: 10 * time.Millisecond})
+	c.Set(id.String(), conv.DomainToDtoOrder(fullOrder))
+
+	fullOrder.Order.TrackNumber = "NEWTRACK"
+	orderRepo.setOrder(fullOrder)
+
+	time.Sleep(20 * time.Millisecond)
+
+	// the stale value is served while the refresh runs in the background
+	order, err := c.Load(context.Background(), id.String())
+	assert.NoError(t, err)
+	assert.Equal(t, "OLDTRACK", order.TrackNumber)
+
+	assert.Eventually(t, func() bool {
+		order, ok := c.GetByTrackNumber("NEWTRACK")
+		return ok && order.OrderUID == id.String()
+	}, time.Second, 5*time.Millisecond)
+}
+
+func TestOrderCache_Load_ReadThrough(t *testing.T) {
+	conv := converter.New()
+	id := uuid.New()
+	orderRepo := &fakeRepo{orders: map[string]domain.FullOrder{
+		id.String(): {Order: domain.Order{ID: id, TrackNumber: "WBILMTESTTRACK"}},
+	}}
+	c := New(orderRepo, conv, config.CacheConfig{MaxSize: 100})
+
+	order, err := c.Load(context.Background(), id.String())
+	assert.NoError(t, err)
+	assert.Equal(t, id.String(), order.OrderUID)
+
+	cached, ok := c.Get(id.String())
+	assert.True(t, ok)
+	assert.Equal(t, order, cached)
+
+	_, ok = c.GetByTrackNumber("WBILMTESTTRACK")
+	assert.True(t, ok)
+	assert.Equal(t, 1, orderRepo.calls)
+}
+
+func TestOrderCache_Load_NotFound(t *testing.T) {
+	orderRepo := &fakeRepo{orders: map[string]domain.FullOrder{}}
+	c := New(orderRepo, converter.New(), config.CacheConfig{MaxSize: 100})
+
+	_, err := c.Load(context.Background(), uuid.New().String())
+	assert.ErrorIs(t, err, repo.ErrOrderNotFound)
+}
+
+func TestOrderCache_Load_Coalescing(t *testing.T) {
+	const requests = 50
+
+	id := uuid.New()
+	orderRepo := &fakeRepo{
+		orders:  map[string]domain.FullOrder{id.String(): {Order: domain.Order{ID: id}}},
+		release: make(chan struct{}),
+	}
+	c := New(orderRepo, converter.New(), config.CacheConfig{MaxSize: 100})
+
+	var started, wg sync.WaitGroup
+	started.Add(requests)
+	wg.Add(requests)
+	errs := make(chan error, requests)
+
+	for i := 0; i < requests; i++ {
+		go func() {
+			defer wg.Done()
+			started.Done()
+
+			order, err := c.Load(context.Background(), id.String())
+			if err == nil && order.OrderUID != id.String() {
+				err = assert.AnError
+			}
+			errs <- err
+		}()
+	}
+
+	started.Wait()
+	// give the goroutines time to join the in-flight load
+	time.Sleep(20 * time.Millisecond)
+	close(orderRepo.release)
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		assert.NoError(t, err)
+	}
+	assert.Equal(t, 1, orderRepo.calls)
+}
+
+func TestOrderCache_Load_NegativeCache(t *testing.T) {
+	conv := converter.New()
+	id := uuid.New()
+	orderRepo := &fakeRepo{orders: map[string]domain.FullOrder{}}
+	c := New(orderRepo, conv, config.CacheConfig{MaxSize: 100, NegativeTTL: time.Minute, NegativeMaxSize: 100})
+
+	for i := 0; i < 3; i++ {
+		_, err := c.Load(context.Background(), id.String())
+		assert.ErrorIs(t, err, repo.ErrOrderNotFound)
+	}
+	assert.Equal(t, 1, orderRepo.calls)
+	assert.Equal(t, uint64(2), c.SavedQueries())
+
+	// creating the order drops the negative entry right away
+	order := conv.DomainToDtoOrder(domain.FullOrder{Order: domain.Order{ID: id}})
+	c.Set(id.String(), order)
+
+	loaded, err := c.Load(context.Background(), id.String())
+	assert.NoError(t, err)
+	assert.Equal(t, order, loaded)
+}
+
+func TestOrderCache_Load_NegativeCacheExpires(t *testing.T) {
+	id := uuid.New()
+	orderRepo := &fakeRepo{orders: map[string]domain.FullOrder{}}
+	c := New(orderRepo, converter.New(), config.CacheConfig{MaxSize: 100, NegativeTTL: 20 * time.Millisecond, NegativeMaxSize: 100})
+
+	_, err := c.Load(context.Background(), id.String())
+	assert.ErrorIs(t, err, repo.ErrOrderNotFound)
+
+	orderRepo.setOrder(domain.FullOrder{Order: domain.Order{ID: id}})
+
+	assert.Eventually(t, func() bool {
+		_, err := c.Load(context.Background(), id.String())
+		return err == nil
+	}, time.Second, 5*time.Millisecond)
+}
+
+func TestOrderCache_InvalidateAndPurge(t *testing.T) {
+	c := New(nil, converter.New(), config.CacheConfig{MaxSize: 100})
+
+	first := dto.Order{OrderUID: uuid.New().String(), TrackNumber: "FIRST"}
+	second := dto.Order{OrderUID: uuid.New().String(), TrackNumber: "SECOND"}
+	c.Set(first.OrderUID, first)
+	c.Set(second.OrderUID, second)
+
+	assert.True(t, c.Invalidate(first.OrderUID))
+	assert.False(t, c.Invalidate(first.OrderUID))
+
+	_, ok := c.GetByTrackNumber("FIRST")
+	assert.False(t, ok)
+
+	c.Purge()
+	_, ok = c.Get(second.OrderUID)
+	assert.False(t, ok)
+	assert.Equal(t, 0, c.Stats().Size)
+}
+
+func TestOrderCache_Stats(t *testing.T) {
+	c := New(nil, converter.New(), config.CacheConfig{MaxSize: 100})
+
+	order := dto.Order{OrderUID: uuid.New().String()}
+	c.Set(order.OrderUID, order)
+
+	c.Get(order.OrderUID)
+	c.Get(order.OrderUID)
+	c.Get(uuid.New().String())
+
+	stats := c.Stats()
+	assert.Equal(t, 1, stats.Size)
+	assert.Equal(t, uint64(2), stats.Hits)
+	assert.Equal(t, uint64(1), stats.Misses)
+	assert.InDelta(t, 2.0/3.0, stats.HitRatio, 0.001)
+}
@@ -0,0 +1,132 @@
+package cache
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"github.com/ilam072/wbtech-l0/backend/internal/types/domain"
+	"github.com/ilam072/wbtech-l0/backend/internal/types/dto"
+	"github.com/ilam072/wbtech-l0/backend/pkg/e"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const snapshotVersion = 5
+
+var ErrSnapshotStale = errors.New("cache snapshot is stale")
+
+type snapshot struct {
+	Version   int
+	SavedAt   time.Time
+	Watermark domain.Watermark
+	Orders    []dto.Order
+}
+
+// SaveSnapshot writes the cached orders to path as gzipped gob together with
+// the current database watermark. The file is replaced atomically.
+func (c *OrderCache) SaveSnapshot(ctx context.Context, path string) error {
+	const op = "cache.SaveSnapshot()"
+
+	// The watermark is taken first: an order stored while the entries are
+	// collected makes the snapshot stale rather than silently incomplete.
+	watermark, err := c.orderRepo.GetWatermark(ctx)
+	if err != nil {
+		return e.Wrap(op, err)
+	}
+
+	snap := snapshot{
+		Version:   snapshotVersion,
+		SavedAt:   time.Now().UTC(),
+		Watermark: watermark,
+		Orders:    make([]dto.Order, 0, c.store.EstimatedSize()),
+	}
+	for _, order := range c.store.All() {
+		snap.Orders = append(snap.Orders, order)
+	}
+
+	if err := writeSnapshot(path, snap); err != nil {
+		return e.Wrap(op, err)
+	}
+	return nil
+}
+
+// LoadSnapshot fills the cache from a snapshot written by SaveSnapshot. It
+// returns ErrSnapshotStale when orders were added, removed or changed since
+// the snapshot was taken, and leaves the cache untouched on any error.
+func (c *OrderCache) LoadSnapshot(ctx context.Context, path string) (int, error) {
+	const op = "cache.LoadSnapshot()"
+
+	snap, err := readSnapshot(path)
+	if err != nil {
+		return 0, e.Wrap(op, err)
+	}
+
+	watermark, err := c.orderRepo.GetWatermark(ctx)
+	if err != nil {
+		return 0, e.Wrap(op, err)
+	}
+
+	if snap.Watermark.OrderCount != watermark.OrderCount || !snap.Watermark.LastUpdated.Equal(watermark.LastUpdated) {
+		return 0, e.Wrap(op, ErrSnapshotStale)
+	}
+
+	for _, order := range snap.Orders {
+		c.Set(order.OrderUID, order)
+	}
+	return len(snap.Orders), nil
+}
+
+func writeSnapshot(path string, snap snapshot) (err error) {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = tmp.Close()
+			_ = os.Remove(tmp.Name())
+		}
+	}()
+
+	zw := gzip.NewWriter(tmp)
+	if err = gob.NewEncoder(zw).Encode(snap); err != nil {
+		return err
+	}
+	if err = zw.Close(); err != nil {
+		return err
+	}
+	if err = tmp.Sync(); err != nil {
+		return err
+	}
+	if err = tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
+
+func readSnapshot(path string) (snapshot, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return snapshot{}, err
+	}
+	defer f.Close()
+
+	zr, err := gzip.NewReader(f)
+	if err != nil {
+		return snapshot{}, err
+	}
+	defer zr.Close()
+
+	var snap snapshot
+	if err := gob.NewDecoder(zr).Decode(&snap); err != nil {
+		return snapshot{}, err
+	}
+	if snap.Version != snapshotVersion {
+		return snapshot{}, fmt.Errorf("unsupported snapshot version %d", snap.Version)
+	}
+	return snap, nil
+}
@@ -0,0 +1,78 @@
+package cache
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"github.com/ilam072/wbtech-l0/backend/internal/config"
+	"github.com/ilam072/wbtech-l0/backend/internal/converter"
+	"github.com/ilam072/wbtech-l0/backend/internal/types/domain"
+	"github.com/ilam072/wbtech-l0/backend/internal/types/dto"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestOrderCache_Snapshot_RoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "orders.snapshot")
+	orderRepo := &fakeRepo{
+		orders: map[string]domain.FullOrder{},
+		mark:   domain.Watermark{OrderCount: 2, LastUpdated: time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)},
+	}
+
+	c := New(orderRepo, converter.New(), config.CacheConfig{MaxSize: 100})
+	first := dto.Order{OrderUID: uuid.New().String(), TrackNumber: "FIRST"}
+	second := dto.Order{OrderUID: uuid.New().String(), TrackNumber: "SECOND"}
+	c.Set(first.OrderUID, first)
+	c.Set(second.OrderUID, second)
+
+	assert.NoError(t, c.SaveSnapshot(context.Background(), path))
+
+	restored := New(orderRepo, converter.New(), config.CacheConfig{MaxSize: 100})
+	n, err := restored.LoadSnapshot(context.Background(), path)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, n)
+
+	cached, ok := restored.Get(first.OrderUID)
+	assert.True(t, ok)
+	assert.Equal(t, first, cached)
+
+	cached, ok = restored.GetByTrackNumber("SECOND")
+	assert.True(t, ok)
+	assert.Equal(t, second, cached)
+}
+
+func TestOrderCache_Snapshot_Stale(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "orders.snapshot")
+	orderRepo := &fakeRepo{orders: map[string]domain.FullOrder{}, mark: domain.Watermark{OrderCount: 1}}
+
+	c := New(orderRepo, converter.New(), config.CacheConfig{MaxSize: 100})
+	order := dto.Order{OrderUID: uuid.New().String()}
+	c.Set(order.OrderUID, order)
+	assert.NoError(t, c.SaveSnapshot(context.Background(), path))
+
+	orderRepo.mark.OrderCount = 2
+
+	restored := New(orderRepo, converter.New(), config.CacheConfig{MaxSize: 100})
+	_, err := restored.LoadSnapshot(context.Background(), path)
+	assert.ErrorIs(t, err, ErrSnapshotStale)
+
+	_, ok := restored.Get(order.OrderUID)
+	assert.False(t, ok)
+}
+
+func TestOrderCache_Snapshot_CorruptOrMissing(t *testing.T) {
+	dir := t.TempDir()
+	orderRepo := &fakeRepo{orders: map[string]domain.FullOrder{}}
+	c := New(orderRepo, converter.New(), config.CacheConfig{MaxSize: 100})
+
+	_, err := c.LoadSnapshot(context.Background(), filepath.Join(dir, "missing.snapshot"))
+	assert.ErrorIs(t, err, os.ErrNotExist)
+
+	corrupt := filepath.Join(dir, "corrupt.snapshot")
+	assert.NoError(t, os.WriteFile(corrupt, []byte("not a snapshot"), 0o600))
+
+	_, err = c.LoadSnapshot(context.Background(), corrupt)
+	assert.Error(t, err)
+}
@@ -4,13 +4,17 @@ import (
 	"fmt"
 	"github.com/caarlos0/env/v11"
 	"github.com/joho/godotenv"
+	"time"
 )
 
 type Config struct {
-	DBConfig     DBConfig
-	ServerConfig ServerConfig
-	KafkaConfig  KafkaConfig
-	CacheConfig  CacheConfig
+	DBConfig         DBConfig
+	ServerConfig     ServerConfig
+	KafkaConfig      KafkaConfig
+	CacheConfig      CacheConfig
+	ValidationConfig ValidationConfig
+	TracingConfig    TracingConfig
+	OutboxConfig     OutboxConfig
 }
 
 type DBConfig struct {
@@ -24,16 +28,97 @@ type DBConfig struct {
 
 type ServerConfig struct {
 	HTTPPort string `env:"HTTP_PORT"`
+	// AdminToken guards the /api/admin routes, which are disabled when it
+	// is empty.
+	AdminToken string `env:"ADMIN_TOKEN"`
+	// HealthCheckTimeout bounds each dependency check of /readyz.
+	HealthCheckTimeout time.Duration `env:"HEALTH_CHECK_TIMEOUT" envDefault:"2s"`
+	// ShutdownTimeout is how long in-flight requests may take to complete
+	// on shutdown before their connections are closed.
+	ShutdownTimeout time.Duration `env:"HTTP_SHUTDOWN_TIMEOUT" envDefault:"10s"`
 }
 
 type KafkaConfig struct {
-	Brokers []string `env:"KAFKA_BROKERS"`
-	Topic   string   `env:"KAFKA_TOPIC"`
-	GroupID string   `env:"KAFKA_GROUP_ID"`
+	Brokers  []string `env:"KAFKA_BROKERS"`
+	Topic    string   `env:"KAFKA_TOPIC"`
+	GroupID  string   `env:"KAFKA_GROUP_ID"`
+	DLQTopic string   `env:"KAFKA_DLQ_TOPIC" envDefault:"orders-dlq"`
+	Workers  int      `env:"KAFKA_WORKERS" envDefault:"4"`
+	// DrainTimeout is how long messages fetched before shutdown may take to
+	// be handled and committed.
+	DrainTimeout time.Duration `env:"KAFKA_DRAIN_TIMEOUT" envDefault:"30s"`
+
+	// StatusTopic carries order status events, consumed by StatusGroupID.
+	StatusTopic   string `env:"KAFKA_STATUS_TOPIC" envDefault:"order-status"`
+	StatusGroupID string `env:"KAFKA_STATUS_GROUP_ID" envDefault:"order-status-consumer"`
+
+	RetryMaxAttempts    int           `env:"KAFKA_RETRY_MAX_ATTEMPTS" envDefault:"5"`
+	RetryInitialBackoff time.Duration `env:"KAFKA_RETRY_INITIAL_BACKOFF" envDefault:"100ms"`
+	RetryMaxBackoff     time.Duration `env:"KAFKA_RETRY_MAX_BACKOFF" envDefault:"5s"`
+	RetryMultiplier     float64       `env:"KAFKA_RETRY_MULTIPLIER" envDefault:"2"`
+}
+
+type OutboxConfig struct {
+	// Topic receives the events published by the outbox relay, keyed by
+	// order uid.
+	Topic        string        `env:"OUTBOX_TOPIC" envDefault:"order-events"`
+	PollInterval time.Duration `env:"OUTBOX_POLL_INTERVAL" envDefault:"1s"`
+	BatchSize    int           `env:"OUTBOX_BATCH_SIZE" envDefault:"100"`
+	// Lease hides claimed events from other relays while they are published.
+	// Events of a relay that stopped mid-batch are published again after it.
+	Lease time.Duration `env:"OUTBOX_LEASE" envDefault:"30s"`
+
+	RetryInitialBackoff time.Duration `env:"OUTBOX_RETRY_INITIAL_BACKOFF" envDefault:"1s"`
+	RetryMaxBackoff     time.Duration `env:"OUTBOX_RETRY_MAX_BACKOFF" envDefault:"1m"`
+
+	// Retention is how long published events are kept, the relay deletes
+	// older ones every PruneInterval. Zero keeps them forever.
+	Retention     time.Duration `env:"OUTBOX_RETENTION" envDefault:"168h"`
+	PruneInterval time.Duration `env:"OUTBOX_PRUNE_INTERVAL" envDefault:"1h"`
 }
 
 type CacheConfig struct {
 	PreloadLimit int `env:"CACHE_PRELOAD_LIMIT"`
+	// MaxSize bounds the number of cached orders. It is ignored when
+	// MaxWeight is set.
+	MaxSize int `env:"CACHE_MAX_SIZE" envDefault:"1000"`
+	// MaxWeight bounds the total size in bytes of cached orders serialized
+	// to JSON.
+	MaxWeight uint64 `env:"CACHE_MAX_WEIGHT"`
+	// ExpireAfterWrite and ExpireAfterAccess drop orders not written or not
+	// read for the given duration. ExpireAfterAccess wins when both are set.
+	ExpireAfterWrite  time.Duration `env:"CACHE_EXPIRE_AFTER_WRITE"`
+	ExpireAfterAccess time.Duration `env:"CACHE_EXPIRE_AFTER_ACCESS"`
+	// RefreshAfterWrite reloads an order from the database in the background
+	// on the first read after the duration has passed since it was written.
+	RefreshAfterWrite time.Duration `env:"CACHE_REFRESH_AFTER_WRITE"`
+	// NegativeTTL is how long a missing order ID is remembered. Zero
+	// disables negative caching.
+	NegativeTTL     time.Duration `env:"CACHE_NEGATIVE_TTL" envDefault:"30s"`
+	NegativeMaxSize int           `env:"CACHE_NEGATIVE_MAX_SIZE" envDefault:"10000"`
+	// SnapshotPath is the file the cache is saved to on shutdown and every
+	// SnapshotInterval, and restored from on start. Empty disables snapshots.
+	SnapshotPath     string        `env:"CACHE_SNAPSHOT_PATH"`
+	SnapshotInterval time.Duration `env:"CACHE_SNAPSHOT_INTERVAL" envDefault:"5m"`
+}
+
+type TracingConfig struct {
+	// Exporter is none or stdout.
+	Exporter    string `env:"TRACING_EXPORTER" envDefault:"none"`
+	ServiceName string `env:"TRACING_SERVICE_NAME" envDefault:"order-service"`
+	// SampleRatio is the share of traces started by the service that are
+	// recorded. Traces continued from a message or request follow the
+	// sampling decision of their parent.
+	SampleRatio float64 `env:"TRACING_SAMPLE_RATIO" envDefault:"1"`
+}
+
+type ValidationConfig struct {
+	// Tolerance is the allowed difference, in minor currency units, between
+	// monetary values compared by business rules.
+	Tolerance int `env:"VALIDATION_TOLERANCE" envDefault:"1"`
+	// RuleActions maps a business rule name to reject, warn or off.
+	// Rules missing from the map are rejected.
+	RuleActions map[string]string `env:"VALIDATION_RULE_ACTIONS" envKeyValSeparator:":" envDefault:"goods_total:reject,amount:reject,item_total_price:reject"`
 }
 
 func (s *ServerConfig) Address() string {
@@ -1,6 +1,9 @@
 package converter
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
 	"github.com/google/uuid"
 	"github.com/ilam072/wbtech-l0/backend/internal/types/domain"
 	"github.com/ilam072/wbtech-l0/backend/internal/types/dto"
@@ -31,6 +34,13 @@ func (c *Converter) DtoToDomainOrder(dto dto.Order) (domain.Order, domain.Delive
 		SmID:              dto.SmID,
 		DateCreated:       dto.DateCreated,
 		OofShard:          dto.OofShard,
+		Status:            domain.OrderStatus(dto.Status),
+		Version:           dto.Version,
+	}
+
+	order.ContentHash, err = contentHash(dto)
+	if err != nil {
+		return domain.Order{}, domain.Delivery{}, domain.Payment{}, nil, err
 	}
 
 	delivery := domain.Delivery{
@@ -122,6 +132,24 @@ func (c *Converter) DomainToDtoOrder(fullOrder domain.FullOrder) dto.Order {
 		items = append(items, item)
 	}
 
+	var (
+		refunds  []dto.Refund
+		refunded int
+	)
+	for _, r := range fullOrder.Refunds {
+		refund := dto.Refund{
+			Amount:    r.Amount,
+			Reason:    r.Reason,
+			CreatedAt: r.CreatedAt,
+		}
+		if r.ChrtID != nil {
+			chrtID := int(*r.ChrtID)
+			refund.ChrtID = &chrtID
+		}
+		refunds = append(refunds, refund)
+		refunded += r.Amount
+	}
+
 	return dto.Order{
 		OrderUID:          fullOrder.Order.ID.String(),
 		TrackNumber:       fullOrder.Order.TrackNumber,
@@ -137,5 +165,29 @@ func (c *Converter) DomainToDtoOrder(fullOrder domain.FullOrder) dto.Order {
 		SmID:              fullOrder.Order.SmID,
 		DateCreated:       fullOrder.Order.DateCreated,
 		OofShard:          fullOrder.Order.OofShard,
+		Version:           fullOrder.Order.Version,
+		Status:            string(fullOrder.Order.Status),
+		Refunds:           refunds,
+		RefundedAmount:    refunded,
+		NetAmount:         fullOrder.Payment.Amount - refunded,
 	}
 }
+
+// contentHash digests the fields of order sent by producers. The version and
+// the fields maintained by the service are left out, so re-sending the same
+// order gives the same hash.
+func contentHash(order dto.Order) (string, error) {
+	order.Version = 0
+	order.Status = ""
+	order.Refunds = nil
+	order.RefundedAmount = 0
+	order.NetAmount = 0
+
+	payload, err := json.Marshal(order)
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256(payload)
+	return hex.EncodeToString(sum[:]), nil
+}
@@ -0,0 +1,52 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestChecker_Ready(t *testing.T) {
+	c := New(time.Second)
+	c.Add("postgres", func(context.Context) error { return nil })
+	c.Add("kafka", func(context.Context) error { return nil })
+
+	report := c.Ready(context.Background())
+	assert.Equal(t, StatusOK, report.Status)
+	assert.Equal(t, StatusOK, report.Checks["postgres"].Status)
+	assert.Equal(t, StatusOK, report.Checks["kafka"].Status)
+}
+
+func TestChecker_Ready_Failure(t *testing.T) {
+	c := New(time.Second)
+	c.Add("postgres", func(context.Context) error { return nil })
+	c.Add("kafka", func(context.Context) error { return errors.New("connection refused") })
+
+	report := c.Ready(context.Background())
+	assert.Equal(t, StatusUnavailable, report.Status)
+	assert.Equal(t, StatusOK, report.Checks["postgres"].Status)
+	assert.Equal(t, StatusUnavailable, report.Checks["kafka"].Status)
+	assert.Equal(t, "connection refused", report.Checks["kafka"].Error)
+}
+
+func TestChecker_Ready_Timeout(t *testing.T) {
+	c := New(10 * time.Millisecond)
+	c.Add("slow", func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+
+	report := c.Ready(context.Background())
+	assert.Equal(t, StatusUnavailable, report.Status)
+	assert.Equal(t, context.DeadlineExceeded.Error(), report.Checks["slow"].Error)
+}
+
+func TestFlag(t *testing.T) {
+	var f Flag
+	assert.ErrorIs(t, f.Check(context.Background()), ErrNotReady)
+
+	f.Set()
+	assert.NoError(t, f.Check(context.Background()))
+}
@@ -0,0 +1,106 @@
+package metrics
+
+import (
+	"github.com/ilam072/wbtech-l0/backend/internal/types/dto"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type CacheStatsProvider interface {
+	Stats() dto.CacheStats
+}
+
+type cacheCollector struct {
+	cache CacheStatsProvider
+
+	size, missingSize                     *prometheus.Desc
+	hits, misses, evictions, savedQueries *prometheus.Desc
+	hitRatio                              *prometheus.Desc
+}
+
+// NewCacheCollector exports the order cache counters read on every scrape.
+func NewCacheCollector(cache CacheStatsProvider) prometheus.Collector {
+	desc := func(name, help string) *prometheus.Desc {
+		return prometheus.NewDesc(prometheus.BuildFQName(namespace, "cache", name), help, nil, nil)
+	}
+
+	return &cacheCollector{
+		cache:        cache,
+		size:         desc("size", "Orders currently cached."),
+		missingSize:  desc("missing_size", "Missing order IDs currently remembered."),
+		hits:         desc("hits_total", "Cache hits."),
+		misses:       desc("misses_total", "Cache misses."),
+		evictions:    desc("evictions_total", "Orders evicted by size or expiry."),
+		savedQueries: desc("saved_queries_total", "Database queries avoided by remembering missing order IDs."),
+		hitRatio:     desc("hit_ratio", "Share of cache requests that were hits."),
+	}
+}
+
+func (c *cacheCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.size
+	ch <- c.missingSize
+	ch <- c.hits
+	ch <- c.misses
+	ch <- c.evictions
+	ch <- c.savedQueries
+	ch <- c.hitRatio
+}
+
+func (c *cacheCollector) Collect(ch chan<- prometheus.Metric) {
+	s := c.cache.Stats()
+
+	ch <- prometheus.MustNewConstMetric(c.size, prometheus.GaugeValue, float64(s.Size))
+	ch <- prometheus.MustNewConstMetric(c.missingSize, prometheus.GaugeValue, float64(s.MissingSize))
+	ch <- prometheus.MustNewConstMetric(c.hits, prometheus.CounterValue, float64(s.Hits))
+	ch <- prometheus.MustNewConstMetric(c.misses, prometheus.CounterValue, float64(s.Misses))
+	ch <- prometheus.MustNewConstMetric(c.evictions, prometheus.CounterValue, float64(s.Evictions))
+	ch <- prometheus.MustNewConstMetric(c.savedQueries, prometheus.CounterValue, float64(s.SavedQueries))
+	ch <- prometheus.MustNewConstMetric(c.hitRatio, prometheus.GaugeValue, s.HitRatio)
+}
+
+type poolCollector struct {
+	pool *pgxpool.Pool
+
+	total, idle, acquired, max              *prometheus.Desc
+	acquires, emptyAcquires, acquireSeconds *prometheus.Desc
+}
+
+// NewPoolCollector exports pgxpool connection statistics.
+func NewPoolCollector(pool *pgxpool.Pool) prometheus.Collector {
+	desc := func(name, help string) *prometheus.Desc {
+		return prometheus.NewDesc(prometheus.BuildFQName(namespace, "pgxpool", name), help, nil, nil)
+	}
+
+	return &poolCollector{
+		pool:           pool,
+		total:          desc("total_conns", "Open connections."),
+		idle:           desc("idle_conns", "Idle connections."),
+		acquired:       desc("acquired_conns", "Connections in use."),
+		max:            desc("max_conns", "Maximum pool size."),
+		acquires:       desc("acquires_total", "Successful connection acquisitions."),
+		emptyAcquires:  desc("empty_acquires_total", "Acquisitions that had to wait for a connection."),
+		acquireSeconds: desc("acquire_duration_seconds_total", "Time spent acquiring connections."),
+	}
+}
+
+func (c *poolCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.total
+	ch <- c.idle
+	ch <- c.acquired
+	ch <- c.max
+	ch <- c.acquires
+	ch <- c.emptyAcquires
+	ch <- c.acquireSeconds
+}
+
+func (c *poolCollector) Collect(ch chan<- prometheus.Metric) {
+	s := c.pool.Stat()
+
+	ch <- prometheus.MustNewConstMetric(c.total, prometheus.GaugeValue, float64(s.TotalConns()))
+	ch <- prometheus.MustNewConstMetric(c.idle, prometheus.GaugeValue, float64(s.IdleConns()))
+	ch <- prometheus.MustNewConstMetric(c.acquired, prometheus.GaugeValue, float64(s.AcquiredConns()))
+	ch <- prometheus.MustNewConstMetric(c.max, prometheus.GaugeValue, float64(s.MaxConns()))
+	ch <- prometheus.MustNewConstMetric(c.acquires, prometheus.CounterValue, float64(s.AcquireCount()))
+	ch <- prometheus.MustNewConstMetric(c.emptyAcquires, prometheus.CounterValue, float64(s.EmptyAcquireCount()))
+	ch <- prometheus.MustNewConstMetric(c.acquireSeconds, prometheus.CounterValue, s.AcquireDuration().Seconds())
+}
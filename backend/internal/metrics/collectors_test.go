@@ -0,0 +1,71 @@
+package metrics
+
+import (
+	"github.com/ilam072/wbtech-l0/backend/internal/types/dto"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/segmentio/kafka-go"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+type fakeCache struct {
+	stats dto.CacheStats
+}
+
+func (c fakeCache) Stats() dto.CacheStats {
+	return c.stats
+}
+
+func TestCacheCollector(t *testing.T) {
+	c := NewCacheCollector(fakeCache{stats: dto.CacheStats{
+		Size:         10,
+		Hits:         30,
+		Misses:       10,
+		HitRatio:     0.75,
+		Evictions:    2,
+		MissingSize:  1,
+		SavedQueries: 4,
+	}})
+
+	expected := `
+# HELP orders_cache_hit_ratio Share of cache requests that were hits.
+# TYPE orders_cache_hit_ratio gauge
+orders_cache_hit_ratio 0.75
+# HELP orders_cache_hits_total Cache hits.
+# TYPE orders_cache_hits_total counter
+orders_cache_hits_total 30
+# HELP orders_cache_misses_total Cache misses.
+# TYPE orders_cache_misses_total counter
+orders_cache_misses_total 10
+# HELP orders_cache_saved_queries_total Database queries avoided by remembering missing order IDs.
+# TYPE orders_cache_saved_queries_total counter
+orders_cache_saved_queries_total 4
+`
+	err := testutil.CollectAndCompare(c, strings.NewReader(expected),
+		"orders_cache_hit_ratio", "orders_cache_hits_total", "orders_cache_misses_total", "orders_cache_saved_queries_total")
+	assert.NoError(t, err)
+	assert.Equal(t, 7, testutil.CollectAndCount(c))
+}
+
+func TestObserveLag(t *testing.T) {
+	KafkaConsumerLag.Reset()
+
+	ObserveLag(kafka.Message{Topic: "orders", Partition: 2, Offset: 57, HighWaterMark: 100})
+	ObserveLag(kafka.Message{Topic: "orders", Partition: 0, Offset: 10, HighWaterMark: 20})
+	ObserveLag(kafka.Message{Topic: "orders", Partition: 0, Offset: 19, HighWaterMark: 20})
+
+	expected := `
+# HELP orders_kafka_consumer_lag Messages the consumer is behind the partition high watermark.
+# TYPE orders_kafka_consumer_lag gauge
+orders_kafka_consumer_lag{partition="0",topic="orders"} 0
+orders_kafka_consumer_lag{partition="2",topic="orders"} 42
+`
+	assert.NoError(t, testutil.CollectAndCompare(KafkaConsumerLag, strings.NewReader(expected)))
+}
+
+func TestRegistry_Lint(t *testing.T) {
+	problems, err := testutil.GatherAndLint(Registry)
+	assert.NoError(t, err)
+	assert.Empty(t, problems)
+}
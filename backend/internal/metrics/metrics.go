@@ -0,0 +1,128 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/segmentio/kafka-go"
+	"net/http"
+	"strconv"
+)
+
+const namespace = "orders"
+
+// Kafka message results.
+const (
+	ResultStored       = "stored"
+	ResultUpdated      = "updated"
+	ResultDuplicate    = "duplicate"
+	ResultStale        = "stale"
+	ResultDeadLettered = "dead_lettered"
+)
+
+var (
+	KafkaMessagesConsumed = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "kafka",
+		Name:      "messages_consumed_total",
+		Help:      "Messages fetched from the orders topic.",
+	})
+
+	KafkaMessagesProcessed = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "kafka",
+		Name:      "messages_processed_total",
+		Help:      "Messages processed by the consumer by result.",
+	}, []string{"result"})
+
+	KafkaMessagesFailed = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "kafka",
+		Name:      "messages_failed_total",
+		Help:      "Messages rejected by the consumer by pipeline stage.",
+	}, []string{"stage"})
+
+	KafkaConsumerLag = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "kafka",
+		Name:      "consumer_lag",
+		Help:      "Messages the consumer is behind the partition high watermark.",
+	}, []string{"topic", "partition"})
+
+	OutboxEventsPublished = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "outbox",
+		Name:      "events_published_total",
+		Help:      "Outbox events published to Kafka.",
+	})
+
+	OutboxPublishFailures = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "outbox",
+		Name:      "publish_failures_total",
+		Help:      "Failed attempts to publish an outbox event.",
+	})
+
+	OutboxEventsPruned = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "outbox",
+		Name:      "events_pruned_total",
+		Help:      "Published outbox events deleted after the retention period.",
+	})
+
+	ServiceDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "service",
+		Name:      "operation_duration_seconds",
+		Help:      "Duration of order service operations.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"operation", "status"})
+
+	HTTPRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "request_duration_seconds",
+		Help:      "Duration of HTTP requests by route and status code.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+)
+
+// Registry holds every metric of the application. Collectors depending on
+// runtime objects (cache, pool, kafka reader) are registered in main.
+var Registry = prometheus.NewRegistry()
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		KafkaMessagesConsumed,
+		KafkaMessagesProcessed,
+		KafkaMessagesFailed,
+		KafkaConsumerLag,
+		OutboxEventsPublished,
+		OutboxPublishFailures,
+		OutboxEventsPruned,
+		ServiceDuration,
+		HTTPRequestDuration,
+	)
+}
+
+// Handler serves Registry in the Prometheus text format.
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{Registry: Registry})
+}
+
+// ObserveLag records how far behind the high watermark of its partition the
+// consumer was when msg was fetched.
+func ObserveLag(msg kafka.Message) {
+	lag := max(msg.HighWaterMark-msg.Offset-1, 0)
+	KafkaConsumerLag.WithLabelValues(msg.Topic, strconv.Itoa(msg.Partition)).Set(float64(lag))
+}
+
+// Status labels the outcome of an operation for ServiceDuration.
+func Status(err error) string {
+	if err != nil {
+		return "error"
+	}
+	return "ok"
+}
@@ -0,0 +1,175 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"github.com/doug-martin/goqu/v9"
+	"github.com/ilam072/wbtech-l0/backend/internal/repo"
+	"github.com/ilam072/wbtech-l0/backend/internal/tracing"
+	"github.com/ilam072/wbtech-l0/backend/internal/types/domain"
+	"github.com/ilam072/wbtech-l0/backend/pkg/e"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// CreateOrders stores a batch of orders in one transaction. The whole batch is
+// first sent as a single pgx pipeline; if any order is rejected, the batch is
+// replayed order by order, each under its own savepoint, so that the remaining
+// orders are still stored and every order gets its own outcome.
+//
+// An error is returned only when the batch as a whole could not be processed
+// (e.g. the connection was lost); in that case nothing is stored.
+func (r *OrderRepo) CreateOrders(ctx context.Context, orders []domain.FullOrder) ([]repo.CreateResult, error) {
+	const op = "postgres.CreateOrders()"
+
+	if len(orders) == 0 {
+		return nil, nil
+	}
+
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return nil, e.Wrap(op, classify(err))
+	}
+
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(ctx)
+		}
+	}()
+
+	results := make([]repo.CreateResult, len(orders))
+	for i, order := range orders {
+		results[i] = repo.CreateResult{OrderID: order.Order.ID, Outcome: repo.OutcomeInserted}
+	}
+
+	batch := &pgx.Batch{}
+	batch.Queue("SAVEPOINT create_orders")
+	for _, order := range orders {
+		if err = queueOrder(batch, order); err != nil {
+			return nil, e.Wrap(op, err)
+		}
+	}
+
+	if batchErr := execBatch(ctx, tx, batch); batchErr != nil {
+		if isTransient(batchErr) {
+			err = batchErr
+			return nil, e.Wrap(op, classify(err))
+		}
+
+		if _, err = tx.Exec(ctx, "ROLLBACK TO SAVEPOINT create_orders"); err != nil {
+			return nil, e.Wrap(op, classify(err))
+		}
+
+		for i, order := range orders {
+			if results[i], err = createOrderInSavepoint(ctx, tx, order); err != nil {
+				return nil, e.Wrap(op, classify(err))
+			}
+		}
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return nil, e.Wrap(op, classify(err))
+	}
+
+	return results, nil
+}
+
+// createOrderInSavepoint stores a single order inside tx and reports its
+// outcome. The returned error is set only for failures that abort the batch.
+func createOrderInSavepoint(ctx context.Context, tx pgx.Tx, order domain.FullOrder) (repo.CreateResult, error) {
+	result := repo.CreateResult{OrderID: order.Order.ID, Outcome: repo.OutcomeInserted}
+
+	batch := &pgx.Batch{}
+	batch.Queue("SAVEPOINT create_order")
+	if err := queueOrder(batch, order); err != nil {
+		result.Outcome, result.Err = repo.OutcomeFailed, err
+		return result, nil
+	}
+	batch.Queue("RELEASE SAVEPOINT create_order")
+
+	insertErr := execBatch(ctx, tx, batch)
+	if insertErr == nil {
+		return result, nil
+	}
+	if isTransient(insertErr) {
+		return result, insertErr
+	}
+
+	if _, err := tx.Exec(ctx, "ROLLBACK TO SAVEPOINT create_order"); err != nil {
+		return result, err
+	}
+
+	if isOrderDuplicate(insertErr) {
+		result.Outcome, result.Err = repo.OutcomeDuplicate, repo.ErrOrderExists
+	} else {
+		result.Outcome, result.Err = repo.OutcomeFailed, insertErr
+	}
+	return result, nil
+}
+
+func queueOrder(batch *pgx.Batch, order domain.FullOrder) error {
+	orderQuery, args, err := goqu.Insert("orders").Rows(order.Order).ToSQL()
+	if err != nil {
+		return err
+	}
+	batch.Queue(orderQuery, args...)
+
+	deliveryQuery, args, err := goqu.Insert("delivery").Rows(order.Delivery).ToSQL()
+	if err != nil {
+		return err
+	}
+	batch.Queue(deliveryQuery, args...)
+
+	paymentQuery, args, err := goqu.Insert("payment").Rows(order.Payment).ToSQL()
+	if err != nil {
+		return err
+	}
+	batch.Queue(paymentQuery, args...)
+
+	if len(order.Items) > 0 {
+		itemsQuery, args, err := goqu.Insert("items").Rows(order.Items).ToSQL()
+		if err != nil {
+			return err
+		}
+		batch.Queue(itemsQuery, args...)
+	}
+
+	outboxQuery, args, err := orderEventQuery(order, domain.EventOrderCreated)
+	if err != nil {
+		return err
+	}
+	batch.Queue(outboxQuery, args...)
+
+	return nil
+}
+
+// execBatch sends batch and returns the first statement error.
+func execBatch(ctx context.Context, tx pgx.Tx, batch *pgx.Batch) (err error) {
+	ctx, span := tracing.Tracer().Start(ctx, "INSERT batch",
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("db.system", "postgresql"),
+			attribute.String("db.operation", "INSERT"),
+			attribute.Int("db.operation.batch.size", batch.Len()),
+		),
+	)
+	defer tracing.End(span, &err)
+
+	results := tx.SendBatch(ctx, batch)
+
+	for i := 0; i < batch.Len() && err == nil; i++ {
+		_, err = results.Exec()
+	}
+
+	if closeErr := results.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+func isOrderDuplicate(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "23505" && pgErr.ConstraintName == "orders_pkey"
+}
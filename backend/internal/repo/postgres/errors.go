@@ -0,0 +1,53 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/ilam072/wbtech-l0/backend/internal/repo"
+	"github.com/jackc/pgx/v5/pgconn"
+	"net"
+	"strings"
+	"syscall"
+)
+
+// classify marks errors that may succeed on retry with repo.ErrTransient.
+func classify(err error) error {
+	if err == nil || !isTransient(err) {
+		return err
+	}
+	return fmt.Errorf("%w: %w", repo.ErrTransient, err)
+}
+
+func isTransient(err error) bool {
+	if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, syscall.ECONNREFUSED) || errors.Is(err, syscall.ECONNRESET) {
+		return true
+	}
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		switch pgErr.Code {
+		case "40001", // serialization_failure
+			"40P01", // deadlock_detected
+			"53300", // too_many_connections
+			"57P01", // admin_shutdown
+			"57P02", // crash_shutdown
+			"57P03": // cannot_connect_now
+			return true
+		}
+		// class 08 - connection exception
+		return strings.HasPrefix(pgErr.Code, "08")
+	}
+
+	var connectErr *pgconn.ConnectError
+	if errors.As(err, &connectErr) {
+		return true
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return true
+	}
+
+	return pgconn.SafeToRetry(err) || pgconn.Timeout(err)
+}
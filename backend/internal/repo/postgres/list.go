@@ -0,0 +1,254 @@
+package postgres
+
+import (
+	"context"
+	"github.com/doug-martin/goqu/v9"
+	"github.com/google/uuid"
+	"github.com/ilam072/wbtech-l0/backend/internal/types/domain"
+	"github.com/ilam072/wbtech-l0/backend/pkg/e"
+	"github.com/jackc/pgx/v5"
+)
+
+// ListOrders returns orders matching filter, newest first, using keyset
+// pagination on (date_created, id).
+func (r *OrderRepo) ListOrders(ctx context.Context, filter domain.OrderFilter) ([]domain.FullOrder, error) {
+	const op = "postgres.ListOrders()"
+
+	ds := fullOrdersDataset()
+
+	if filter.CustomerID != "" {
+		ds = ds.Where(goqu.I("o.customer_id").Eq(filter.CustomerID))
+	}
+	if filter.TrackNumber != "" {
+		ds = ds.Where(goqu.I("o.track_number").Eq(filter.TrackNumber))
+	}
+	if filter.DeliveryService != "" {
+		ds = ds.Where(goqu.I("o.delivery_service").Eq(filter.DeliveryService))
+	}
+	if !filter.CreatedFrom.IsZero() {
+		ds = ds.Where(goqu.I("o.date_created").Gte(filter.CreatedFrom))
+	}
+	if !filter.CreatedTo.IsZero() {
+		ds = ds.Where(goqu.I("o.date_created").Lte(filter.CreatedTo))
+	}
+	if filter.Currency != "" {
+		ds = ds.Where(goqu.I("p.currency").Eq(filter.Currency))
+	}
+	if filter.Provider != "" {
+		ds = ds.Where(goqu.I("p.provider").Eq(filter.Provider))
+	}
+	if filter.Brand != "" {
+		ds = ds.Where(goqu.L("EXISTS ?", goqu.From("items").
+			Select(goqu.L("1")).
+			Where(
+				goqu.I("items.order_id").Eq(goqu.I("o.id")),
+				goqu.I("items.brand").Eq(filter.Brand),
+			),
+		))
+	}
+	if filter.After != nil {
+		ds = ds.Where(goqu.L("(o.date_created, o.id) < (?, ?)", filter.After.DateCreated, filter.After.ID))
+	}
+
+	sql, args, err := ds.
+		Order(goqu.I("o.date_created").Desc(), goqu.I("o.id").Desc()).
+		Limit(uint(filter.Limit)).
+		ToSQL()
+	if err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	orders, err := r.queryFullOrders(ctx, sql, args...)
+	if err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	return orders, nil
+}
+
+// fullOrdersDataset selects orders joined with their delivery and payment,
+// aliased as o, d and p. The column order matches scanFullOrder.
+func fullOrdersDataset() *goqu.SelectDataset {
+	return goqu.From(goqu.T("orders").As("o")).
+		Join(goqu.T("delivery").As("d"), goqu.On(goqu.Ex{"o.id": goqu.I("d.order_id")})).
+		Join(goqu.T("payment").As("p"), goqu.On(goqu.Ex{"o.id": goqu.I("p.order_id")})).
+		Select(
+			goqu.I("o.id"),
+			goqu.I("o.track_number"),
+			goqu.I("o.entry"),
+			goqu.I("o.locale"),
+			goqu.I("o.internal_signature"),
+			goqu.I("o.customer_id"),
+			goqu.I("o.delivery_service"),
+			goqu.I("o.shardkey"),
+			goqu.I("o.sm_id"),
+			goqu.I("o.date_created"),
+			goqu.I("o.oof_shard"),
+			goqu.I("o.status"),
+			goqu.I("o.version"),
+
+			goqu.I("d.id"),
+			goqu.I("d.order_id"),
+			goqu.I("d.name"),
+			goqu.I("d.phone"),
+			goqu.I("d.zip"),
+			goqu.I("d.city"),
+			goqu.I("d.address"),
+			goqu.I("d.region"),
+			goqu.I("d.email"),
+
+			goqu.I("p.transaction"),
+			goqu.I("p.order_id"),
+			goqu.I("p.request_id"),
+			goqu.I("p.currency"),
+			goqu.I("p.provider"),
+			goqu.I("p.amount"),
+			goqu.I("p.payment_dt"),
+			goqu.I("p.bank"),
+			goqu.I("p.delivery_cost"),
+			goqu.I("p.goods_total"),
+			goqu.I("p.custom_fee"),
+		)
+}
+
+func scanFullOrder(row pgx.Row) (domain.FullOrder, error) {
+	var o domain.FullOrder
+
+	err := row.Scan(
+		&o.Order.ID,
+		&o.Order.TrackNumber,
+		&o.Order.Entry,
+		&o.Order.Locale,
+		&o.Order.InternalSignature,
+		&o.Order.CustomerID,
+		&o.Order.DeliveryService,
+		&o.Order.ShardKey,
+		&o.Order.SmID,
+		&o.Order.DateCreated,
+		&o.Order.OofShard,
+		&o.Order.Status,
+		&o.Order.Version,
+
+		&o.Delivery.ID,
+		&o.Delivery.OrderID,
+		&o.Delivery.Name,
+		&o.Delivery.Phone,
+		&o.Delivery.Zip,
+		&o.Delivery.City,
+		&o.Delivery.Address,
+		&o.Delivery.Region,
+		&o.Delivery.Email,
+
+		&o.Payment.Transaction,
+		&o.Payment.OrderID,
+		&o.Payment.RequestID,
+		&o.Payment.Currency,
+		&o.Payment.Provider,
+		&o.Payment.Amount,
+		&o.Payment.PaymentDt,
+		&o.Payment.Bank,
+		&o.Payment.DeliveryCost,
+		&o.Payment.GoodsTotal,
+		&o.Payment.CustomFee,
+	)
+
+	return o, err
+}
+
+// queryFullOrders runs a fullOrdersDataset query and attaches the items and
+// refunds of all returned orders with one additional query each.
+func (r *OrderRepo) queryFullOrders(ctx context.Context, sql string, args ...interface{}) ([]domain.FullOrder, error) {
+	rows, err := r.pool.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var orders []domain.FullOrder
+	for rows.Next() {
+		order, err := scanFullOrder(rows)
+		if err != nil {
+			return nil, err
+		}
+		orders = append(orders, order)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	rows.Close()
+
+	if len(orders) == 0 {
+		return nil, nil
+	}
+
+	ids := make([]uuid.UUID, 0, len(orders))
+	for _, order := range orders {
+		ids = append(ids, order.Order.ID)
+	}
+
+	items, err := r.getItemsByOrderIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	refunds, err := r.getRefundsByOrderIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range orders {
+		orders[i].Items = items[orders[i].Order.ID]
+		orders[i].Refunds = refunds[orders[i].Order.ID]
+	}
+
+	return orders, nil
+}
+
+func (r *OrderRepo) getItemsByOrderIDs(ctx context.Context, orderIDs []uuid.UUID) (map[uuid.UUID][]domain.Item, error) {
+	const op = "postgres.getItemsByOrderIDs()"
+
+	// ANY($1) keeps the statement the same size for any number of orders,
+	// an IN list would grow with every preloaded order.
+	sql, _, err := goqu.From("items").
+		Where(goqu.L("order_id = ANY($1)")).
+		ToSQL()
+	if err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	rows, err := r.pool.Query(ctx, sql, orderIDs)
+	if err != nil {
+		return nil, e.Wrap(op, err)
+	}
+	defer rows.Close()
+
+	items := make(map[uuid.UUID][]domain.Item, len(orderIDs))
+	for rows.Next() {
+		var item domain.Item
+
+		err = rows.Scan(
+			&item.ChrtID,
+			&item.OrderID,
+			&item.TrackNumber,
+			&item.Price,
+			&item.Rid,
+			&item.Name,
+			&item.Sale,
+			&item.Size,
+			&item.TotalPrice,
+			&item.NmID,
+			&item.Brand,
+			&item.Status,
+		)
+		if err != nil {
+			return nil, e.Wrap(op, err)
+		}
+
+		items[item.OrderID] = append(items[item.OrderID], item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	return items, nil
+}
@@ -0,0 +1,226 @@
+package postgres
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"github.com/doug-martin/goqu/v9"
+	"github.com/ilam072/wbtech-l0/backend/internal/types/domain"
+	"github.com/ilam072/wbtech-l0/backend/pkg/e"
+	"github.com/jackc/pgx/v5"
+	"slices"
+	"time"
+)
+
+// orderPayload is the body of the order.created and order.updated events.
+type orderPayload struct {
+	OrderUID        string             `json:"order_uid"`
+	TrackNumber     string             `json:"track_number"`
+	CustomerID      string             `json:"customer_id"`
+	DeliveryService string             `json:"delivery_service"`
+	DateCreated     time.Time          `json:"date_created"`
+	Version         int64              `json:"version"`
+	Amount          int                `json:"amount"`
+	Currency        string             `json:"currency"`
+	Items           []orderPayloadItem `json:"items"`
+}
+
+type orderPayloadItem struct {
+	ChrtID     int64  `json:"chrt_id"`
+	NmID       int64  `json:"nm_id"`
+	Name       string `json:"name"`
+	Brand      string `json:"brand"`
+	Size       string `json:"size"`
+	TotalPrice int    `json:"total_price"`
+}
+
+func orderEvent(order domain.FullOrder, eventType string) (domain.OutboxEvent, error) {
+	payload := orderPayload{
+		OrderUID:        order.Order.ID.String(),
+		TrackNumber:     order.Order.TrackNumber,
+		CustomerID:      order.Order.CustomerID,
+		DeliveryService: order.Order.DeliveryService,
+		DateCreated:     order.Order.DateCreated,
+		Version:         order.Order.Version,
+		Amount:          order.Payment.Amount,
+		Currency:        order.Payment.Currency,
+		Items:           make([]orderPayloadItem, 0, len(order.Items)),
+	}
+	for _, item := range order.Items {
+		payload.Items = append(payload.Items, orderPayloadItem{
+			ChrtID:     item.ChrtID,
+			NmID:       item.NmID,
+			Name:       item.Name,
+			Brand:      item.Brand,
+			Size:       item.Size,
+			TotalPrice: item.TotalPrice,
+		})
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return domain.OutboxEvent{}, err
+	}
+
+	return domain.OutboxEvent{
+		AggregateID: order.Order.ID,
+		Type:        eventType,
+		Payload:     body,
+	}, nil
+}
+
+// orderEventQuery builds the outbox INSERT of an event of order.
+func orderEventQuery(order domain.FullOrder, eventType string) (string, []interface{}, error) {
+	event, err := orderEvent(order, eventType)
+	if err != nil {
+		return "", nil, err
+	}
+	return goqu.Insert("outbox").Rows(event).ToSQL()
+}
+
+// insertOrderEvent writes an event of order to the outbox inside tx, so it is
+// stored if and only if the change of the order is.
+func insertOrderEvent(ctx context.Context, tx pgx.Tx, order domain.FullOrder, eventType string) error {
+	query, args, err := orderEventQuery(order, eventType)
+	if err != nil {
+		return err
+	}
+	return insert(ctx, tx, "outbox", query, args)
+}
+
+// ClaimOutbox leases up to limit unsent events for lease and returns them
+// oldest first. An event is not claimed while an older event of the same
+// order is unsent, so the events of an order are published in order.
+// Concurrent relays skip each other's events.
+func (r *OrderRepo) ClaimOutbox(ctx context.Context, limit int, lease time.Duration) ([]domain.OutboxEvent, error) {
+	const op = "postgres.ClaimOutbox()"
+
+	pending := goqu.From(goqu.T("outbox").As("o")).
+		Select("o.id").
+		Where(
+			goqu.I("o.sent_at").IsNull(),
+			goqu.Or(goqu.I("o.locked_until").IsNull(), goqu.I("o.locked_until").Lte(goqu.L("NOW()"))),
+			goqu.L("NOT EXISTS ?", goqu.From(goqu.T("outbox").As("prev")).
+				Select(goqu.L("1")).
+				Where(
+					goqu.I("prev.aggregate_id").Eq(goqu.I("o.aggregate_id")),
+					goqu.I("prev.sent_at").IsNull(),
+					goqu.I("prev.id").Lt(goqu.I("o.id")),
+				),
+			),
+		).
+		Order(goqu.I("o.id").Asc()).
+		Limit(uint(limit)).
+		ForUpdate(goqu.SkipLocked)
+
+	sql, args, err := goqu.Update("outbox").
+		Set(goqu.Record{"locked_until": goqu.L("NOW() + make_interval(secs => ?)", lease.Seconds())}).
+		Where(goqu.I("id").In(pending)).
+		Returning("id", "aggregate_id", "event_type", "payload", "attempts", "created_at").
+		ToSQL()
+	if err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	rows, err := r.pool.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, e.Wrap(op, classify(err))
+	}
+	defer rows.Close()
+
+	var events []domain.OutboxEvent
+	for rows.Next() {
+		var event domain.OutboxEvent
+		if err := rows.Scan(
+			&event.ID,
+			&event.AggregateID,
+			&event.Type,
+			&event.Payload,
+			&event.Attempts,
+			&event.CreatedAt,
+		); err != nil {
+			return nil, e.Wrap(op, err)
+		}
+		events = append(events, event)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, e.Wrap(op, classify(err))
+	}
+
+	// RETURNING does not keep the order of the subquery
+	slices.SortFunc(events, func(a, b domain.OutboxEvent) int {
+		return cmp.Compare(a.ID, b.ID)
+	})
+
+	return events, nil
+}
+
+// MarkOutboxSent records that the events have been published.
+func (r *OrderRepo) MarkOutboxSent(ctx context.Context, ids []int64) error {
+	const op = "postgres.MarkOutboxSent()"
+
+	if len(ids) == 0 {
+		return nil
+	}
+
+	sql, args, err := goqu.Update("outbox").
+		Set(goqu.Record{"sent_at": goqu.L("NOW()"), "locked_until": nil}).
+		Where(goqu.Ex{"id": ids}).
+		ToSQL()
+	if err != nil {
+		return e.Wrap(op, err)
+	}
+
+	if _, err := r.pool.Exec(ctx, sql, args...); err != nil {
+		return e.Wrap(op, classify(err))
+	}
+	return nil
+}
+
+// RetryOutbox records a failed publish of the event and hides it from relays
+// for delay.
+func (r *OrderRepo) RetryOutbox(ctx context.Context, id int64, reason string, delay time.Duration) error {
+	const op = "postgres.RetryOutbox()"
+
+	sql, args, err := goqu.Update("outbox").
+		Set(goqu.Record{
+			"attempts":     goqu.L("attempts + 1"),
+			"last_error":   reason,
+			"locked_until": goqu.L("NOW() + make_interval(secs => ?)", delay.Seconds()),
+		}).
+		Where(goqu.Ex{"id": id}).
+		ToSQL()
+	if err != nil {
+		return e.Wrap(op, err)
+	}
+
+	if _, err := r.pool.Exec(ctx, sql, args...); err != nil {
+		return e.Wrap(op, classify(err))
+	}
+	return nil
+}
+
+// PruneOutbox deletes the events published more than retention ago and
+// returns their number.
+func (r *OrderRepo) PruneOutbox(ctx context.Context, retention time.Duration) (int64, error) {
+	const op = "postgres.PruneOutbox()"
+
+	sql, args, err := pruneOutboxQuery(retention)
+	if err != nil {
+		return 0, e.Wrap(op, err)
+	}
+
+	tag, err := r.pool.Exec(ctx, sql, args...)
+	if err != nil {
+		return 0, e.Wrap(op, classify(err))
+	}
+	return tag.RowsAffected(), nil
+}
+
+// pruneOutboxQuery builds the DELETE of the events published more than
+// retention ago. Pending events have no sent_at and are never matched.
+func pruneOutboxQuery(retention time.Duration) (string, []interface{}, error) {
+	return goqu.Delete("outbox").
+		Where(goqu.I("sent_at").Lt(goqu.L("NOW() - make_interval(secs => ?)", retention.Seconds()))).
+		ToSQL()
+}
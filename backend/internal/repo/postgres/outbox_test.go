@@ -0,0 +1,45 @@
+package postgres
+
+import (
+	"encoding/json"
+	"github.com/google/uuid"
+	"github.com/ilam072/wbtech-l0/backend/internal/types/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestOrderEvent(t *testing.T) {
+	order := domain.FullOrder{
+		Order:   domain.Order{ID: uuid.New(), TrackNumber: "WBTRACK", Version: 2},
+		Payment: domain.Payment{Amount: 1000, Currency: "RUB"},
+		Items:   []domain.Item{{ChrtID: 1, TotalPrice: 1000}},
+	}
+
+	for _, eventType := range []string{domain.EventOrderCreated, domain.EventOrderUpdated} {
+		t.Run(eventType, func(t *testing.T) {
+			event, err := orderEvent(order, eventType)
+			require.NoError(t, err)
+
+			assert.Equal(t, order.Order.ID, event.AggregateID)
+			assert.Equal(t, eventType, event.Type)
+
+			var payload orderPayload
+			require.NoError(t, json.Unmarshal(event.Payload, &payload))
+			assert.Equal(t, order.Order.ID.String(), payload.OrderUID)
+			assert.Equal(t, int64(2), payload.Version)
+			assert.Equal(t, 1000, payload.Amount)
+			assert.Len(t, payload.Items, 1)
+		})
+	}
+}
+
+func TestPruneOutboxQuery(t *testing.T) {
+	sql, _, err := pruneOutboxQuery(36 * time.Hour)
+
+	require.NoError(t, err)
+	assert.Equal(t,
+		`DELETE FROM "outbox" WHERE ("sent_at" < NOW() - make_interval(secs => 129600))`,
+		sql)
+}
@@ -6,11 +6,14 @@ import (
 	"github.com/doug-martin/goqu/v9"
 	"github.com/google/uuid"
 	"github.com/ilam072/wbtech-l0/backend/internal/repo"
+	"github.com/ilam072/wbtech-l0/backend/internal/tracing"
 	"github.com/ilam072/wbtech-l0/backend/internal/types/domain"
 	"github.com/ilam072/wbtech-l0/backend/pkg/e"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 type OrderRepo struct {
@@ -32,7 +35,7 @@ func (r *OrderRepo) CreateOrder(
 
 	tx, err := r.pool.Begin(ctx)
 	if err != nil {
-		return e.Wrap(op, err)
+		return e.Wrap(op, classify(err))
 	}
 
 	defer func() {
@@ -45,47 +48,69 @@ func (r *OrderRepo) CreateOrder(
 	if err != nil {
 		return e.Wrap(op, err)
 	}
-	if _, err = tx.Exec(ctx, orderQuery, args...); err != nil {
+	if err = insert(ctx, tx, "orders", orderQuery, args); err != nil {
 		var pgErr *pgconn.PgError
 		if errors.As(err, &pgErr) {
 			if pgErr.Code == "23505" && pgErr.ConstraintName == "orders_pkey" {
 				return e.Wrap(op, repo.ErrOrderExists)
 			}
 		}
-		return e.Wrap(op, err)
+		return e.Wrap(op, classify(err))
 	}
 
 	deliveryQuery, args, err := goqu.Insert("delivery").Rows(delivery).ToSQL()
 	if err != nil {
 		return e.Wrap(op, err)
 	}
-	if _, err = tx.Exec(ctx, deliveryQuery, args...); err != nil {
-		return e.Wrap(op, err)
+	if err = insert(ctx, tx, "delivery", deliveryQuery, args); err != nil {
+		return e.Wrap(op, classify(err))
 	}
 
 	paymentQuery, args, err := goqu.Insert("payment").Rows(payment).ToSQL()
 	if err != nil {
 		return e.Wrap(op, err)
 	}
-	if _, err = tx.Exec(ctx, paymentQuery, args...); err != nil {
-		return e.Wrap(op, err)
+	if err = insert(ctx, tx, "payment", paymentQuery, args); err != nil {
+		return e.Wrap(op, classify(err))
 	}
 
 	itemsQuery, args, err := goqu.Insert("items").Rows(items).ToSQL()
 	if err != nil {
 		return e.Wrap(op, err)
 	}
-	if _, err = tx.Exec(ctx, itemsQuery, args...); err != nil {
-		return e.Wrap(op, err)
+	if err = insert(ctx, tx, "items", itemsQuery, args); err != nil {
+		return e.Wrap(op, classify(err))
 	}
 
-	if err := tx.Commit(ctx); err != nil {
-		return e.Wrap(op, err)
+	full := domain.FullOrder{Order: order, Delivery: delivery, Payment: payment, Items: items}
+	err = insertOrderEvent(ctx, tx, full, domain.EventOrderCreated)
+	if err != nil {
+		return e.Wrap(op, classify(err))
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return e.Wrap(op, classify(err))
 	}
 
 	return nil
 }
 
+// insert runs an INSERT statement into table inside its own span.
+func insert(ctx context.Context, tx pgx.Tx, table string, query string, args []interface{}) (err error) {
+	ctx, span := tracing.Tracer().Start(ctx, "INSERT "+table,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("db.system", "postgresql"),
+			attribute.String("db.operation", "INSERT"),
+			attribute.String("db.sql.table", table),
+		),
+	)
+	defer tracing.End(span, &err)
+
+	_, err = tx.Exec(ctx, query, args...)
+	return err
+}
+
 func (r *OrderRepo) GetOrder(ctx context.Context, ID string) (domain.FullOrder, error) {
 	const op = "postgres.GetOrder()"
 
@@ -112,6 +137,8 @@ func (r *OrderRepo) GetOrder(ctx context.Context, ID string) (domain.FullOrder,
 			goqu.I("orders.sm_id"),
 			goqu.I("orders.date_created"),
 			goqu.I("orders.oof_shard"),
+			goqu.I("orders.status"),
+			goqu.I("orders.version"),
 
 			// delivery
 			goqu.I("delivery.id"),
@@ -161,6 +188,8 @@ func (r *OrderRepo) GetOrder(ctx context.Context, ID string) (domain.FullOrder,
 		&order.SmID,
 		&order.DateCreated,
 		&order.OofShard,
+		&order.Status,
+		&order.Version,
 
 		&delivery.ID,
 		&delivery.OrderID,
@@ -225,181 +254,37 @@ func (r *OrderRepo) GetOrder(ctx context.Context, ID string) (domain.FullOrder,
 
 		items = append(items, itm)
 	}
+	rows.Close()
+
+	refunds, err := r.getRefundsByOrderIDs(ctx, []uuid.UUID{order.ID})
+	if err != nil {
+		return domain.FullOrder{}, e.Wrap(op, err)
+	}
 
 	return domain.FullOrder{
 		Order:    order,
 		Delivery: delivery,
 		Payment:  payment,
 		Items:    items,
+		Refunds:  refunds[order.ID],
 	}, nil
 }
 
 func (r *OrderRepo) GetLastOrders(ctx context.Context, limit int) ([]domain.FullOrder, error) {
 	const op = "postgres.GetLastOrders()"
 
-	sql, args, err := goqu.From(goqu.T("orders").As("o")).
-		Join(goqu.T("delivery").As("d"), goqu.On(goqu.Ex{"o.id": goqu.I("d.order_id")})).
-		Join(goqu.T("payment").As("p"), goqu.On(goqu.Ex{"o.id": goqu.I("p.order_id")})).
-		Select(
-
-			goqu.I("o.id"),
-			goqu.I("o.track_number"),
-			goqu.I("o.entry"),
-			goqu.I("o.locale"),
-			goqu.I("o.internal_signature"),
-			goqu.I("o.customer_id"),
-			goqu.I("o.delivery_service"),
-			goqu.I("o.shardkey"),
-			goqu.I("o.sm_id"),
-			goqu.I("o.date_created"),
-			goqu.I("o.oof_shard"),
-
-			goqu.I("d.id"),
-			goqu.I("d.order_id"),
-			goqu.I("d.name"),
-			goqu.I("d.phone"),
-			goqu.I("d.zip"),
-			goqu.I("d.city"),
-			goqu.I("d.address"),
-			goqu.I("d.region"),
-			goqu.I("d.email"),
-
-			goqu.I("p.transaction"),
-			goqu.I("p.order_id"),
-			goqu.I("p.request_id"),
-			goqu.I("p.currency"),
-			goqu.I("p.provider"),
-			goqu.I("p.amount"),
-			goqu.I("p.payment_dt"),
-			goqu.I("p.bank"),
-			goqu.I("p.delivery_cost"),
-			goqu.I("p.goods_total"),
-			goqu.I("p.custom_fee"),
-		).
-		Order(goqu.I("o.date_created").Desc()).
+	sql, args, err := fullOrdersDataset().
+		Order(goqu.I("o.date_created").Desc(), goqu.I("o.id").Desc()).
 		Limit(uint(limit)).
 		ToSQL()
 	if err != nil {
 		return nil, e.Wrap(op, err)
 	}
 
-	rows, err := r.pool.Query(ctx, sql, args...)
+	orders, err := r.queryFullOrders(ctx, sql, args...)
 	if err != nil {
 		return nil, e.Wrap(op, err)
 	}
-	defer rows.Close()
-
-	var orders []domain.FullOrder
-
-	for rows.Next() {
-		var (
-			order    domain.Order
-			delivery domain.Delivery
-			payment  domain.Payment
-		)
-
-		err = rows.Scan(
-
-			&order.ID,
-			&order.TrackNumber,
-			&order.Entry,
-			&order.Locale,
-			&order.InternalSignature,
-			&order.CustomerID,
-			&order.DeliveryService,
-			&order.ShardKey,
-			&order.SmID,
-			&order.DateCreated,
-			&order.OofShard,
-
-			&delivery.ID,
-			&delivery.OrderID,
-			&delivery.Name,
-			&delivery.Phone,
-			&delivery.Zip,
-			&delivery.City,
-			&delivery.Address,
-			&delivery.Region,
-			&delivery.Email,
-
-			&payment.Transaction,
-			&payment.OrderID,
-			&payment.RequestID,
-			&payment.Currency,
-			&payment.Provider,
-			&payment.Amount,
-			&payment.PaymentDt,
-			&payment.Bank,
-			&payment.DeliveryCost,
-			&payment.GoodsTotal,
-			&payment.CustomFee,
-		)
-		if err != nil {
-			return nil, e.Wrap(op, err)
-		}
-
-		items, err := r.getItemsByOrderID(ctx, order.ID)
-		if err != nil {
-			return nil, e.Wrap(op, err)
-		}
-
-		fullOrder := domain.FullOrder{
-			Order:    order,
-			Delivery: delivery,
-			Payment:  payment,
-			Items:    items,
-		}
-
-		orders = append(orders, fullOrder)
-	}
-
-	if len(orders) == 0 {
-		return nil, nil
-	}
 
 	return orders, nil
 }
-
-func (r *OrderRepo) getItemsByOrderID(ctx context.Context, orderID uuid.UUID) ([]domain.Item, error) {
-	const op = "postgres.getItemsByOrderID"
-
-	sql, args, err := goqu.From("items").
-		Where(goqu.Ex{"order_id": orderID}).
-		ToSQL()
-	if err != nil {
-		return nil, e.Wrap(op, err)
-	}
-
-	rows, err := r.pool.Query(ctx, sql, args...)
-	if err != nil {
-		return nil, e.Wrap(op, err)
-	}
-	defer rows.Close()
-
-	var items []domain.Item
-	for rows.Next() {
-		var item domain.Item
-
-		err = rows.Scan(
-			&item.ChrtID,
-			&item.OrderID,
-			&item.TrackNumber,
-			&item.Price,
-			&item.Rid,
-			&item.Name,
-			&item.Sale,
-			&item.Size,
-			&item.TotalPrice,
-			&item.NmID,
-			&item.Brand,
-			&item.Status,
-		)
-		if err != nil {
-			return nil, e.Wrap(op, err)
-		}
-
-		items = append(items, item)
-	}
-
-	return items, nil
-}
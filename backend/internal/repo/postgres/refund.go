@@ -0,0 +1,211 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"github.com/doug-martin/goqu/v9"
+	"github.com/google/uuid"
+	"github.com/ilam072/wbtech-l0/backend/internal/repo"
+	"github.com/ilam072/wbtech-l0/backend/internal/types/domain"
+	"github.com/ilam072/wbtech-l0/backend/pkg/e"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// CreateRefunds records refunds of one order while it is still in status.
+// It fails with repo.ErrStatusConflict if the status has changed,
+// repo.ErrAlreadyRefunded if an item was refunded before and
+// repo.ErrRefundExceedsPayment if the refunds would exceed the payment.
+func (r *OrderRepo) CreateRefunds(ctx context.Context, status domain.OrderStatus, refunds []domain.Refund) error {
+	const op = "postgres.CreateRefunds()"
+
+	if len(refunds) == 0 {
+		return nil
+	}
+
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return e.Wrap(op, classify(err))
+	}
+
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(ctx)
+		}
+	}()
+
+	if err = lockOrder(ctx, tx, refunds[0].OrderID, status); err != nil {
+		return e.Wrap(op, err)
+	}
+
+	if err = insertRefunds(ctx, tx, refunds); err != nil {
+		return e.Wrap(op, err)
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return e.Wrap(op, classify(err))
+	}
+
+	return nil
+}
+
+// CancelOrder moves the order to cancelled as described by change and
+// records refunds of the paid amount, in one transaction.
+func (r *OrderRepo) CancelOrder(ctx context.Context, change domain.StatusChange, refunds []domain.Refund) error {
+	const op = "postgres.CancelOrder()"
+
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return e.Wrap(op, classify(err))
+	}
+
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(ctx)
+		}
+	}()
+
+	if err = updateStatus(ctx, tx, change); err != nil {
+		return e.Wrap(op, err)
+	}
+
+	if err = insertRefunds(ctx, tx, refunds); err != nil {
+		return e.Wrap(op, err)
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return e.Wrap(op, classify(err))
+	}
+
+	return nil
+}
+
+// lockOrder locks the order row for the rest of tx and checks its status.
+func lockOrder(ctx context.Context, tx pgx.Tx, orderID uuid.UUID, status domain.OrderStatus) error {
+	sql, args, err := goqu.From("orders").
+		Select("status").
+		Where(goqu.Ex{"id": orderID}).
+		ForUpdate(goqu.Wait).
+		ToSQL()
+	if err != nil {
+		return err
+	}
+
+	var current domain.OrderStatus
+	if err := tx.QueryRow(ctx, sql, args...).Scan(&current); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return repo.ErrOrderNotFound
+		}
+		return classify(err)
+	}
+	if current != status {
+		return repo.ErrStatusConflict
+	}
+	return nil
+}
+
+// insertRefunds stores refunds of one order if, together with the refunds
+// stored before, they do not exceed its payment amount, and marks the order as
+// updated. The order row must be locked by tx.
+func insertRefunds(ctx context.Context, tx pgx.Tx, refunds []domain.Refund) error {
+	if len(refunds) == 0 {
+		return nil
+	}
+
+	refunded := goqu.From("refunds").
+		Select(goqu.COALESCE(goqu.SUM("amount"), 0)).
+		Where(goqu.Ex{"order_id": refunds[0].OrderID})
+	sql, args, err := goqu.From("payment").
+		Select(goqu.L("? - (?)", goqu.I("amount"), refunded)).
+		Where(goqu.Ex{"order_id": refunds[0].OrderID}).
+		ToSQL()
+	if err != nil {
+		return err
+	}
+
+	var remaining int
+	if err := tx.QueryRow(ctx, sql, args...).Scan(&remaining); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return repo.ErrOrderNotFound
+		}
+		return classify(err)
+	}
+
+	total := 0
+	for _, refund := range refunds {
+		total += refund.Amount
+	}
+	if total > remaining {
+		return repo.ErrRefundExceedsPayment
+	}
+
+	insertQuery, args, err := goqu.Insert("refunds").Rows(refunds).ToSQL()
+	if err != nil {
+		return err
+	}
+	if _, err := tx.Exec(ctx, insertQuery, args...); err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" && pgErr.ConstraintName == "idx_refunds_order_item" {
+			return repo.ErrAlreadyRefunded
+		}
+		return classify(err)
+	}
+
+	touchQuery, args, err := touchOrderQuery(refunds[0].OrderID)
+	if err != nil {
+		return err
+	}
+	if _, err := tx.Exec(ctx, touchQuery, args...); err != nil {
+		return classify(err)
+	}
+
+	return nil
+}
+
+// touchOrderQuery marks the order as changed without updating its columns.
+func touchOrderQuery(orderID uuid.UUID) (string, []interface{}, error) {
+	return goqu.Update("orders").
+		Set(touched(goqu.Record{})).
+		Where(goqu.Ex{"id": orderID}).
+		ToSQL()
+}
+
+func (r *OrderRepo) getRefundsByOrderIDs(ctx context.Context, orderIDs []uuid.UUID) (map[uuid.UUID][]domain.Refund, error) {
+	const op = "postgres.getRefundsByOrderIDs()"
+
+	sql, _, err := goqu.From("refunds").
+		Select("id", "order_id", "chrt_id", "amount", "reason", "created_at").
+		Where(goqu.L("order_id = ANY($1)")).
+		Order(goqu.I("created_at").Asc(), goqu.I("id").Asc()).
+		ToSQL()
+	if err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	rows, err := r.pool.Query(ctx, sql, orderIDs)
+	if err != nil {
+		return nil, e.Wrap(op, err)
+	}
+	defer rows.Close()
+
+	refunds := make(map[uuid.UUID][]domain.Refund)
+	for rows.Next() {
+		var refund domain.Refund
+		if err := rows.Scan(
+			&refund.ID,
+			&refund.OrderID,
+			&refund.ChrtID,
+			&refund.Amount,
+			&refund.Reason,
+			&refund.CreatedAt,
+		); err != nil {
+			return nil, e.Wrap(op, err)
+		}
+		refunds[refund.OrderID] = append(refunds[refund.OrderID], refund)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	return refunds, nil
+}
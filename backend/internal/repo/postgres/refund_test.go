@@ -0,0 +1,19 @@
+package postgres
+
+import (
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestTouchOrderQuery(t *testing.T) {
+	orderID := uuid.MustParse("b563feb7-b2b8-4b6d-9f2c-1a2b3c4d5e6f")
+
+	sql, _, err := touchOrderQuery(orderID)
+
+	require.NoError(t, err)
+	assert.Equal(t,
+		`UPDATE "orders" SET "updated_at"=NOW() WHERE ("id" = 'b563feb7-b2b8-4b6d-9f2c-1a2b3c4d5e6f')`,
+		sql)
+}
@@ -0,0 +1,143 @@
+package postgres
+
+import (
+	"context"
+	"github.com/doug-martin/goqu/v9"
+	"github.com/google/uuid"
+	"github.com/ilam072/wbtech-l0/backend/internal/repo"
+	"github.com/ilam072/wbtech-l0/backend/internal/types/domain"
+	"github.com/ilam072/wbtech-l0/backend/pkg/e"
+	"github.com/jackc/pgx/v5"
+)
+
+// UpdateOrderStatus moves the order from change.From to change.To and records
+// the change in the status history, in one transaction. It fails with
+// repo.ErrStatusConflict when the order is no longer in change.From.
+func (r *OrderRepo) UpdateOrderStatus(ctx context.Context, change domain.StatusChange) error {
+	const op = "postgres.UpdateOrderStatus()"
+
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return e.Wrap(op, classify(err))
+	}
+
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(ctx)
+		}
+	}()
+
+	if err = updateStatus(ctx, tx, change); err != nil {
+		return e.Wrap(op, err)
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return e.Wrap(op, classify(err))
+	}
+
+	return nil
+}
+
+// updateStatus moves the order from change.From to change.To inside tx and
+// records the change in the status history.
+func updateStatus(ctx context.Context, tx pgx.Tx, change domain.StatusChange) error {
+	updateQuery, args, err := statusUpdateQuery(change)
+	if err != nil {
+		return err
+	}
+
+	tag, err := tx.Exec(ctx, updateQuery, args...)
+	if err != nil {
+		return classify(err)
+	}
+	if tag.RowsAffected() == 0 {
+		return statusConflict(ctx, tx, change.OrderID)
+	}
+
+	historyQuery, args, err := goqu.Insert("order_status_history").Rows(change).ToSQL()
+	if err != nil {
+		return err
+	}
+	if _, err = tx.Exec(ctx, historyQuery, args...); err != nil {
+		return classify(err)
+	}
+
+	return nil
+}
+
+func statusUpdateQuery(change domain.StatusChange) (string, []interface{}, error) {
+	return goqu.Update("orders").
+		Set(touched(goqu.Record{"status": change.To})).
+		Where(goqu.Ex{"id": change.OrderID, "status": change.From}).
+		ToSQL()
+}
+
+// touched marks an orders row update as a change of the order. updated_at
+// feeds the watermark a cache snapshot is checked against, so a snapshot
+// taken before the change is not restored on the next start.
+func touched(record goqu.Record) goqu.Record {
+	record["updated_at"] = goqu.L("NOW()")
+	return record
+}
+
+// statusConflict tells apart a missing order from one whose status has
+// changed since it was read.
+func statusConflict(ctx context.Context, tx pgx.Tx, orderID uuid.UUID) error {
+	sql, args, err := goqu.From("orders").
+		Select(goqu.COUNT(goqu.Star())).
+		Where(goqu.Ex{"id": orderID}).
+		ToSQL()
+	if err != nil {
+		return err
+	}
+
+	var count int
+	if err := tx.QueryRow(ctx, sql, args...).Scan(&count); err != nil {
+		return classify(err)
+	}
+	if count == 0 {
+		return repo.ErrOrderNotFound
+	}
+	return repo.ErrStatusConflict
+}
+
+// GetStatusHistory returns the status changes of an order, oldest first.
+func (r *OrderRepo) GetStatusHistory(ctx context.Context, orderID string) ([]domain.StatusChange, error) {
+	const op = "postgres.GetStatusHistory()"
+
+	sql, args, err := goqu.From("order_status_history").
+		Select("id", "order_id", "from_status", "to_status", "reason", "changed_at").
+		Where(goqu.Ex{"order_id": orderID}).
+		Order(goqu.I("changed_at").Asc(), goqu.I("id").Asc()).
+		ToSQL()
+	if err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	rows, err := r.pool.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, e.Wrap(op, classify(err))
+	}
+	defer rows.Close()
+
+	var history []domain.StatusChange
+	for rows.Next() {
+		var change domain.StatusChange
+		if err := rows.Scan(
+			&change.ID,
+			&change.OrderID,
+			&change.From,
+			&change.To,
+			&change.Reason,
+			&change.ChangedAt,
+		); err != nil {
+			return nil, e.Wrap(op, err)
+		}
+		history = append(history, change)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	return history, nil
+}
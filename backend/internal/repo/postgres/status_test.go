@@ -0,0 +1,25 @@
+package postgres
+
+import (
+	"github.com/google/uuid"
+	"github.com/ilam072/wbtech-l0/backend/internal/types/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestStatusUpdateQuery_TouchesOrder(t *testing.T) {
+	orderID := uuid.MustParse("b563feb7-b2b8-4b6d-9f2c-1a2b3c4d5e6f")
+
+	sql, _, err := statusUpdateQuery(domain.StatusChange{
+		OrderID: orderID,
+		From:    domain.StatusCreated,
+		To:      domain.StatusPaid,
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t,
+		`UPDATE "orders" SET "status"='paid',"updated_at"=NOW() `+
+			`WHERE (("id" = 'b563feb7-b2b8-4b6d-9f2c-1a2b3c4d5e6f') AND ("status" = 'created'))`,
+		sql)
+}
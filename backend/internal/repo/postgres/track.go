@@ -0,0 +1,31 @@
+package postgres
+
+import (
+	"context"
+	"github.com/doug-martin/goqu/v9"
+	"github.com/ilam072/wbtech-l0/backend/internal/repo"
+	"github.com/ilam072/wbtech-l0/backend/internal/types/domain"
+	"github.com/ilam072/wbtech-l0/backend/pkg/e"
+)
+
+func (r *OrderRepo) GetOrderByTrackNumber(ctx context.Context, trackNumber string) (domain.FullOrder, error) {
+	const op = "postgres.GetOrderByTrackNumber()"
+
+	sql, args, err := fullOrdersDataset().
+		Where(goqu.I("o.track_number").Eq(trackNumber)).
+		ToSQL()
+	if err != nil {
+		return domain.FullOrder{}, e.Wrap(op, err)
+	}
+
+	orders, err := r.queryFullOrders(ctx, sql, args...)
+	if err != nil {
+		return domain.FullOrder{}, e.Wrap(op, err)
+	}
+
+	if len(orders) == 0 {
+		return domain.FullOrder{}, e.Wrap(op, repo.ErrOrderNotFound)
+	}
+
+	return orders[0], nil
+}
@@ -0,0 +1,236 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/doug-martin/goqu/v9"
+	"github.com/google/uuid"
+	"github.com/ilam072/wbtech-l0/backend/internal/repo"
+	"github.com/ilam072/wbtech-l0/backend/internal/types/domain"
+	"github.com/ilam072/wbtech-l0/backend/pkg/e"
+	"github.com/jackc/pgx/v5"
+)
+
+// UpsertOrder stores order or replaces the stored revision of it, in one
+// transaction:
+//   - a new order is inserted (repo.OutcomeInserted);
+//   - the same content is not written again (repo.OutcomeUnchanged);
+//   - a higher version replaces the order with its delivery, payment and items,
+//     keeping the status and refunds (repo.OutcomeUpdated), unless the stored
+//     refunds do not fit the new revision (repo.ErrRefundsConflict);
+//   - other content with the same or a lower version is ignored (repo.OutcomeStale).
+func (r *OrderRepo) UpsertOrder(ctx context.Context, order domain.FullOrder) (repo.CreateOutcome, error) {
+	const op = "postgres.UpsertOrder()"
+
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return "", e.Wrap(op, classify(err))
+	}
+
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(ctx)
+		}
+	}()
+
+	sql, args, err := goqu.From("orders").
+		Select("version", "content_hash").
+		Where(goqu.Ex{"id": order.Order.ID}).
+		ForUpdate(goqu.Wait).
+		ToSQL()
+	if err != nil {
+		return "", e.Wrap(op, err)
+	}
+
+	var (
+		storedVersion int64
+		storedHash    string
+	)
+	err = tx.QueryRow(ctx, sql, args...).Scan(&storedVersion, &storedHash)
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+		if err = insertOrder(ctx, tx, order); err != nil {
+			if isOrderDuplicate(err) {
+				// a concurrent upsert inserted the order first, a retry takes the update path
+				err = fmt.Errorf("%w: %w", repo.ErrConcurrentInsert, err)
+			}
+			return "", e.Wrap(op, classify(err))
+		}
+		if err = tx.Commit(ctx); err != nil {
+			return "", e.Wrap(op, classify(err))
+		}
+		return repo.OutcomeInserted, nil
+	case err != nil:
+		return "", e.Wrap(op, classify(err))
+	}
+
+	var outcome repo.CreateOutcome
+	switch {
+	case storedHash == order.Order.ContentHash:
+		outcome = repo.OutcomeUnchanged
+	case order.Order.Version <= storedVersion:
+		outcome = repo.OutcomeStale
+	default:
+		if err = replaceOrder(ctx, tx, order); err != nil {
+			return "", e.Wrap(op, classify(err))
+		}
+		outcome = repo.OutcomeUpdated
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return "", e.Wrap(op, classify(err))
+	}
+
+	return outcome, nil
+}
+
+// insertOrder inserts order with its delivery, payment and items inside tx
+// and writes its order.created event to the outbox.
+func insertOrder(ctx context.Context, tx pgx.Tx, order domain.FullOrder) error {
+	orderQuery, args, err := goqu.Insert("orders").Rows(order.Order).ToSQL()
+	if err != nil {
+		return err
+	}
+	if err := insert(ctx, tx, "orders", orderQuery, args); err != nil {
+		return err
+	}
+
+	if err := insertDetails(ctx, tx, order); err != nil {
+		return err
+	}
+
+	return insertOrderEvent(ctx, tx, order, domain.EventOrderCreated)
+}
+
+// replaceOrder overwrites the stored order with a newer revision inside tx
+// and writes its order.updated event to the outbox. The status is kept,
+// delivery, payment and items are replaced. The order row must be locked by
+// tx, so that no refund is stored in between.
+func replaceOrder(ctx context.Context, tx pgx.Tx, order domain.FullOrder) error {
+	refunds, err := orderRefunds(ctx, tx, order.Order.ID)
+	if err != nil {
+		return err
+	}
+	if err := coversRefunds(order, refunds); err != nil {
+		return err
+	}
+
+	updateQuery, args, err := goqu.Update("orders").
+		Set(goqu.Record{
+			"track_number":       order.Order.TrackNumber,
+			"entry":              order.Order.Entry,
+			"locale":             order.Order.Locale,
+			"internal_signature": order.Order.InternalSignature,
+			"customer_id":        order.Order.CustomerID,
+			"delivery_service":   order.Order.DeliveryService,
+			"shardkey":           order.Order.ShardKey,
+			"sm_id":              order.Order.SmID,
+			"date_created":       order.Order.DateCreated,
+			"oof_shard":          order.Order.OofShard,
+			"version":            order.Order.Version,
+			"content_hash":       order.Order.ContentHash,
+			"updated_at":         goqu.L("NOW()"),
+		}).
+		Where(goqu.Ex{"id": order.Order.ID}).
+		ToSQL()
+	if err != nil {
+		return err
+	}
+	if _, err := tx.Exec(ctx, updateQuery, args...); err != nil {
+		return err
+	}
+
+	for _, table := range []string{"items", "payment", "delivery"} {
+		deleteQuery, args, err := goqu.Delete(table).Where(goqu.Ex{"order_id": order.Order.ID}).ToSQL()
+		if err != nil {
+			return err
+		}
+		if _, err := tx.Exec(ctx, deleteQuery, args...); err != nil {
+			return err
+		}
+	}
+
+	if err := insertDetails(ctx, tx, order); err != nil {
+		return err
+	}
+
+	return insertOrderEvent(ctx, tx, order, domain.EventOrderUpdated)
+}
+
+// orderRefunds reads the item and amount of the refunds of an order inside tx.
+func orderRefunds(ctx context.Context, tx pgx.Tx, orderID uuid.UUID) ([]domain.Refund, error) {
+	sql, args, err := goqu.From("refunds").
+		Select("chrt_id", "amount").
+		Where(goqu.Ex{"order_id": orderID}).
+		ToSQL()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := tx.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var refunds []domain.Refund
+	for rows.Next() {
+		refund := domain.Refund{OrderID: orderID}
+		if err := rows.Scan(&refund.ChrtID, &refund.Amount); err != nil {
+			return nil, err
+		}
+		refunds = append(refunds, refund)
+	}
+	return refunds, rows.Err()
+}
+
+// coversRefunds checks that the refunds stored for an order still fit a new
+// revision of it: every refunded item is still in the order and the refunds
+// do not exceed the new payment amount.
+func coversRefunds(order domain.FullOrder, refunds []domain.Refund) error {
+	items := make(map[int64]bool, len(order.Items))
+	for _, item := range order.Items {
+		items[item.ChrtID] = true
+	}
+
+	refunded := 0
+	for _, refund := range refunds {
+		if refund.ChrtID != nil && !items[*refund.ChrtID] {
+			return fmt.Errorf("%w: refunded item %d is missing", repo.ErrRefundsConflict, *refund.ChrtID)
+		}
+		refunded += refund.Amount
+	}
+	if refunded > order.Payment.Amount {
+		return fmt.Errorf("%w: refunded %d exceeds amount %d", repo.ErrRefundsConflict, refunded, order.Payment.Amount)
+	}
+	return nil
+}
+
+// insertDetails inserts the delivery, payment and items of order inside tx.
+func insertDetails(ctx context.Context, tx pgx.Tx, order domain.FullOrder) error {
+	deliveryQuery, args, err := goqu.Insert("delivery").Rows(order.Delivery).ToSQL()
+	if err != nil {
+		return err
+	}
+	if err := insert(ctx, tx, "delivery", deliveryQuery, args); err != nil {
+		return err
+	}
+
+	paymentQuery, args, err := goqu.Insert("payment").Rows(order.Payment).ToSQL()
+	if err != nil {
+		return err
+	}
+	if err := insert(ctx, tx, "payment", paymentQuery, args); err != nil {
+		return err
+	}
+
+	if len(order.Items) == 0 {
+		return nil
+	}
+	itemsQuery, args, err := goqu.Insert("items").Rows(order.Items).ToSQL()
+	if err != nil {
+		return err
+	}
+	return insert(ctx, tx, "items", itemsQuery, args)
+}
@@ -0,0 +1,41 @@
+package postgres
+
+import (
+	"github.com/ilam072/wbtech-l0/backend/internal/repo"
+	"github.com/ilam072/wbtech-l0/backend/internal/types/domain"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestCoversRefunds(t *testing.T) {
+	chrtID := func(id int64) *int64 { return &id }
+
+	order := domain.FullOrder{
+		Payment: domain.Payment{Amount: 1000},
+		Items:   []domain.Item{{ChrtID: 1, TotalPrice: 300}, {ChrtID: 2, TotalPrice: 500}},
+	}
+
+	tests := []struct {
+		name    string
+		refunds []domain.Refund
+		wantErr error
+	}{
+		{"no refunds", nil, nil},
+		{"refunded items kept", []domain.Refund{{ChrtID: chrtID(1), Amount: 300}, {ChrtID: chrtID(2), Amount: 500}}, nil},
+		{"whole amount refunded", []domain.Refund{{Amount: 1000}}, nil},
+		{"refunded item dropped", []domain.Refund{{ChrtID: chrtID(3), Amount: 200}}, repo.ErrRefundsConflict},
+		{"amount below refunds", []domain.Refund{{ChrtID: chrtID(1), Amount: 300}, {Amount: 800}}, repo.ErrRefundsConflict},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := coversRefunds(order, tt.refunds)
+
+			if tt.wantErr == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, tt.wantErr)
+			}
+		})
+	}
+}
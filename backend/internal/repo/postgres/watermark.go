@@ -0,0 +1,29 @@
+package postgres
+
+import (
+	"context"
+	"github.com/doug-martin/goqu/v9"
+	"github.com/ilam072/wbtech-l0/backend/internal/types/domain"
+	"github.com/ilam072/wbtech-l0/backend/pkg/e"
+	"time"
+)
+
+func (r *OrderRepo) GetWatermark(ctx context.Context) (domain.Watermark, error) {
+	const op = "postgres.GetWatermark()"
+
+	sql, args, err := goqu.From("orders").
+		Select(
+			goqu.COUNT(goqu.Star()),
+			goqu.COALESCE(goqu.MAX("updated_at"), time.Unix(0, 0).UTC()),
+		).
+		ToSQL()
+	if err != nil {
+		return domain.Watermark{}, e.Wrap(op, err)
+	}
+
+	var w domain.Watermark
+	if err := r.pool.QueryRow(ctx, sql, args...).Scan(&w.OrderCount, &w.LastUpdated); err != nil {
+		return domain.Watermark{}, e.Wrap(op, err)
+	}
+	return w, nil
+}
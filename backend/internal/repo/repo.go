@@ -1,8 +1,45 @@
 package repo
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+)
 
 var (
 	ErrOrderExists   = errors.New("order already exists")
 	ErrOrderNotFound = errors.New("order not found")
+	ErrTransient     = errors.New("temporary database failure")
+	// ErrConcurrentInsert means the order was inserted by a concurrent
+	// transaction in between. It is transient: a retry finds the order stored.
+	ErrConcurrentInsert = fmt.Errorf("%w: order inserted concurrently", ErrTransient)
+	// ErrStatusConflict means the order status was changed concurrently.
+	ErrStatusConflict = errors.New("order status changed concurrently")
+	// ErrAlreadyRefunded means an item of the order was refunded before.
+	ErrAlreadyRefunded = errors.New("order item already refunded")
+	// ErrRefundExceedsPayment means the refunds would exceed the payment amount.
+	ErrRefundExceedsPayment = errors.New("refunds exceed payment amount")
+	// ErrRefundsConflict means a new revision of the order drops a refunded
+	// item or lowers the payment amount below the refunded sum.
+	ErrRefundsConflict = errors.New("order revision conflicts with its refunds")
+)
+
+type CreateOutcome string
+
+const (
+	OutcomeInserted  CreateOutcome = "inserted"
+	OutcomeDuplicate CreateOutcome = "duplicate"
+	OutcomeFailed    CreateOutcome = "failed"
+
+	// Outcomes of an upsert besides OutcomeInserted.
+	OutcomeUpdated   CreateOutcome = "updated"
+	OutcomeUnchanged CreateOutcome = "unchanged"
+	OutcomeStale     CreateOutcome = "stale"
 )
+
+// CreateResult is the outcome of storing a single order of a batch.
+type CreateResult struct {
+	OrderID uuid.UUID
+	Outcome CreateOutcome
+	Err     error
+}
@@ -0,0 +1,100 @@
+package rest
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"github.com/gofiber/fiber/v2"
+	"github.com/ilam072/wbtech-l0/backend/pkg/logger/sl"
+	"log/slog"
+)
+
+const (
+	adminTokenHeader = "X-Admin-Token"
+	maxPreloadLimit  = 100_000
+)
+
+type preloadQuery struct {
+	Limit int `query:"limit"`
+}
+
+func (h *Handler) adminAuth(ctx *fiber.Ctx) error {
+	token := ctx.Get(adminTokenHeader)
+	if subtle.ConstantTimeCompare([]byte(token), []byte(h.adminToken)) != 1 {
+		return ctx.Status(fiber.StatusUnauthorized).JSON(
+			errorResponse("invalid admin token"))
+	}
+	return ctx.Next()
+}
+
+// @Summary Cache statistics
+// @Description Returns hit, miss and eviction counters and the current size of the order cache
+// @Tags admin
+// @Param X-Admin-Token header string true "admin token"
+// @Success 200 {object} dto.CacheStats
+// @Failure 401 {object} ErrorResp "invalid admin token"
+// @Router /api/admin/cache [get]
+func (h *Handler) CacheStatsHandler(ctx *fiber.Ctx) error {
+	return ctx.Status(fiber.StatusOK).JSON(h.c.Stats())
+}
+
+// @Summary Purge cache
+// @Description Evicts every order from the cache
+// @Tags admin
+// @Param X-Admin-Token header string true "admin token"
+// @Success 204
+// @Failure 401 {object} ErrorResp "invalid admin token"
+// @Router /api/admin/cache [delete]
+func (h *Handler) PurgeCacheHandler(ctx *fiber.Ctx) error {
+	h.c.Purge()
+	h.log.Info("order cache purged by admin")
+
+	return ctx.SendStatus(fiber.StatusNoContent)
+}
+
+// @Summary Evict cached order
+// @Description Evicts a single order from the cache, the next read loads it from the database
+// @Tags admin
+// @Param X-Admin-Token header string true "admin token"
+// @Param id path string true "order uid"
+// @Success 204
+// @Failure 401 {object} ErrorResp "invalid admin token"
+// @Failure 404 {object} ErrorResp "order is not cached"
+// @Router /api/admin/cache/orders/{id} [delete]
+func (h *Handler) EvictOrderHandler(ctx *fiber.Ctx) error {
+	orderId := ctx.Params("id")
+
+	if !h.c.Invalidate(orderId) {
+		return ctx.Status(fiber.StatusNotFound).JSON(
+			errorResponse("order is not cached"))
+	}
+	h.log.Info("order evicted from cache by admin", slog.String("order_uid", orderId))
+
+	return ctx.SendStatus(fiber.StatusNoContent)
+}
+
+// @Summary Preload cache
+// @Description Loads the latest orders from the database into the cache
+// @Tags admin
+// @Param X-Admin-Token header string true "admin token"
+// @Param limit query int true "number of latest orders to load"
+// @Success 200 {object} dto.CacheStats
+// @Failure 400 {object} ErrorResp "invalid limit"
+// @Failure 401 {object} ErrorResp "invalid admin token"
+// @Failure 500 {object} ErrorResp "internal server error"
+// @Router /api/admin/cache/preload [post]
+func (h *Handler) PreloadCacheHandler(ctx *fiber.Ctx) error {
+	var q preloadQuery
+	if err := ctx.QueryParser(&q); err != nil || q.Limit <= 0 || q.Limit > maxPreloadLimit {
+		return ctx.Status(fiber.StatusBadRequest).JSON(
+			errorResponse(fmt.Sprintf("limit must be from 1 to %d", maxPreloadLimit)))
+	}
+
+	if err := h.c.Preload(ctx.UserContext(), q.Limit); err != nil {
+		h.log.Error("failed to preload cache", slog.Int("limit", q.Limit), sl.Err(err))
+		return ctx.Status(fiber.StatusInternalServerError).JSON(
+			errorResponse("something went wrong, try again later"))
+	}
+	h.log.Info("order cache preloaded by admin", slog.Int("limit", q.Limit))
+
+	return ctx.Status(fiber.StatusOK).JSON(h.c.Stats())
+}
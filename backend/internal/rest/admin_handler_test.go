@@ -0,0 +1,133 @@
+package rest
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/ilam072/wbtech-l0/backend/internal/types/dto"
+	httpmock "github.com/ilam072/wbtech-l0/backend/mocks/http"
+	"github.com/ilam072/wbtech-l0/backend/pkg/logger/handlers/slogdiscard"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+const testAdminToken = "secret"
+
+func newAdminHandler(t *testing.T) (*Handler, *httpmock.MockCacheAdmin) {
+	ctrl := gomock.NewController(t)
+	t.Cleanup(ctrl.Finish)
+
+	mockCache := httpmock.NewMockCacheAdmin(ctrl)
+	h := NewHandler(
+		slogdiscard.NewDiscardLogger(),
+		httpmock.NewMockOrderService(ctrl),
+		httpmock.NewMockValidator(ctrl),
+		mockCache,
+		httpmock.NewMockHealthChecker(ctrl),
+		testAdminToken,
+	)
+	return h, mockCache
+}
+
+func adminRequest(method, target, token string) *http.Request {
+	req := httptest.NewRequest(method, target, nil)
+	if token != "" {
+		req.Header.Set(adminTokenHeader, token)
+	}
+	return req
+}
+
+func TestHandler_Admin_Unauthorized(t *testing.T) {
+	h, _ := newAdminHandler(t)
+
+	for _, token := range []string{"", "wrong"} {
+		resp, _ := h.api.Test(adminRequest(http.MethodGet, "/api/admin/cache", token))
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	}
+}
+
+func TestHandler_Admin_DisabledWithoutToken(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	h := NewHandler(
+		slogdiscard.NewDiscardLogger(),
+		httpmock.NewMockOrderService(ctrl),
+		httpmock.NewMockValidator(ctrl),
+		httpmock.NewMockCacheAdmin(ctrl),
+		httpmock.NewMockHealthChecker(ctrl),
+		"",
+	)
+
+	resp, _ := h.api.Test(adminRequest(http.MethodGet, "/api/admin/cache", ""))
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
+
+func TestHandler_CacheStatsHandler(t *testing.T) {
+	h, mockCache := newAdminHandler(t)
+
+	expected := dto.CacheStats{Size: 10, Hits: 7, Misses: 3, HitRatio: 0.7, SavedQueries: 2}
+	mockCache.EXPECT().Stats().Return(expected)
+
+	resp, _ := h.api.Test(adminRequest(http.MethodGet, "/api/admin/cache", testAdminToken))
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var stats dto.CacheStats
+	err := json.NewDecoder(resp.Body).Decode(&stats)
+	assert.NoError(t, err)
+	assert.Equal(t, expected, stats)
+}
+
+func TestHandler_EvictOrderHandler(t *testing.T) {
+	h, mockCache := newAdminHandler(t)
+
+	mockCache.EXPECT().Invalidate("cached").Return(true)
+	mockCache.EXPECT().Invalidate("unknown").Return(false)
+
+	resp, _ := h.api.Test(adminRequest(http.MethodDelete, "/api/admin/cache/orders/cached", testAdminToken))
+	assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+
+	resp, _ = h.api.Test(adminRequest(http.MethodDelete, "/api/admin/cache/orders/unknown", testAdminToken))
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
+
+func TestHandler_PurgeCacheHandler(t *testing.T) {
+	h, mockCache := newAdminHandler(t)
+
+	mockCache.EXPECT().Purge()
+
+	resp, _ := h.api.Test(adminRequest(http.MethodDelete, "/api/admin/cache", testAdminToken))
+	assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+}
+
+func TestHandler_PreloadCacheHandler(t *testing.T) {
+	h, mockCache := newAdminHandler(t)
+
+	gomock.InOrder(
+		mockCache.EXPECT().Preload(gomock.Any(), 500).Return(nil),
+		mockCache.EXPECT().Stats().Return(dto.CacheStats{Size: 500}),
+	)
+
+	resp, _ := h.api.Test(adminRequest(http.MethodPost, "/api/admin/cache/preload?limit=500", testAdminToken))
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+}
+
+func TestHandler_PreloadCacheHandler_InvalidLimit(t *testing.T) {
+	h, _ := newAdminHandler(t)
+
+	for _, query := range []string{"", "?limit=0", "?limit=abc", "?limit=1000000"} {
+		resp, _ := h.api.Test(adminRequest(http.MethodPost, "/api/admin/cache/preload"+query, testAdminToken))
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode, query)
+	}
+}
+
+func TestHandler_PreloadCacheHandler_Error(t *testing.T) {
+	h, mockCache := newAdminHandler(t)
+
+	mockCache.EXPECT().Preload(gomock.Any(), 10).Return(errors.New("db is down"))
+
+	resp, _ := h.api.Test(adminRequest(http.MethodPost, "/api/admin/cache/preload?limit=10", testAdminToken))
+	assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+}
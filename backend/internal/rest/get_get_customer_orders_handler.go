@@ -0,0 +1,50 @@
+package rest
+
+import (
+	"errors"
+	"github.com/gofiber/fiber/v2"
+	"github.com/ilam072/wbtech-l0/backend/internal/service"
+	"github.com/ilam072/wbtech-l0/backend/internal/types/dto"
+	"github.com/ilam072/wbtech-l0/backend/pkg/logger/sl"
+)
+
+type customerOrdersQuery struct {
+	Cursor string `query:"cursor"`
+	Limit  int    `query:"limit"`
+}
+
+// @Summary List customer orders
+// @Description Returns orders of the given customer, newest first. Use next_cursor from the response to fetch the next page.
+// @Tags order
+// @Param id path string true "customer id"
+// @Param limit query int false "page size (1-100, default 20)"
+// @Param cursor query string false "next_cursor of the previous page"
+// @Success 200 {object} dto.OrderPage
+// @Failure 400 {object} ErrorResp "invalid filter"
+// @Failure 500 {object} ErrorResp "internal server error"
+// @Router /api/customers/{id}/orders [get]
+func (h *Handler) GetCustomerOrdersHandler(ctx *fiber.Ctx) error {
+	customerID := ctx.Params("id")
+
+	var query customerOrdersQuery
+	if err := ctx.QueryParser(&query); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(
+			errorResponse("invalid query parameters"))
+	}
+
+	page, err := h.s.GetCustomerOrders(ctx.UserContext(), customerID, dto.OrderFilter{
+		Cursor: query.Cursor,
+		Limit:  query.Limit,
+	})
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidFilter) {
+			return ctx.Status(fiber.StatusBadRequest).JSON(
+				errorResponse(reason(err, service.ErrInvalidFilter)))
+		}
+		h.log.Error("failed to get customer orders", sl.Err(err))
+		return ctx.Status(fiber.StatusInternalServerError).JSON(
+			errorResponse("something went wrong, try again later"))
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(page)
+}
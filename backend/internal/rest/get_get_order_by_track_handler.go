@@ -0,0 +1,33 @@
+package rest
+
+import (
+	"errors"
+	"github.com/gofiber/fiber/v2"
+	"github.com/ilam072/wbtech-l0/backend/internal/service"
+	"github.com/ilam072/wbtech-l0/backend/pkg/logger/sl"
+)
+
+// @Summary Get order by track number
+// @Description Returns order details by given track number
+// @Tags order
+// @Param track path string true "track number"
+// @Success 200 {object} dto.Order
+// @Failure 404 {object} ErrorResp "order not found"
+// @Failure 500 {object} ErrorResp "internal server error"
+// @Router /api/order/by-track/{track} [get]
+func (h *Handler) GetOrderByTrackHandler(ctx *fiber.Ctx) error {
+	trackNumber := ctx.Params("track")
+
+	order, err := h.s.GetOrderByTrackNumber(ctx.UserContext(), trackNumber)
+	if err != nil {
+		if errors.Is(err, service.ErrOrderNotFound) {
+			return ctx.Status(fiber.StatusNotFound).JSON(
+				errorResponse("order not found"))
+		}
+		h.log.Error("failed to get order by track number", sl.Err(err))
+		return ctx.Status(fiber.StatusInternalServerError).JSON(
+			errorResponse("something went wrong, try again later"))
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(order)
+}
@@ -0,0 +1,86 @@
+package rest
+
+import (
+	"encoding/json"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/ilam072/wbtech-l0/backend/internal/service"
+	"github.com/ilam072/wbtech-l0/backend/internal/types/dto"
+	httpmock "github.com/ilam072/wbtech-l0/backend/mocks/http"
+	"github.com/ilam072/wbtech-l0/backend/pkg/logger/handlers/slogdiscard"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHandler_GetOrderByTrackHandler_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	log := slogdiscard.NewDiscardLogger()
+	mockService := httpmock.NewMockOrderService(ctrl)
+	h := NewHandler(log, mockService, httpmock.NewMockValidator(ctrl), httpmock.NewMockCacheAdmin(ctrl), httpmock.NewMockHealthChecker(ctrl), "")
+
+	app := fiber.New()
+	app.Get("/api/order/by-track/:track", h.GetOrderByTrackHandler)
+
+	expectedOrder := dto.Order{OrderUID: uuid.New().String(), TrackNumber: "WBILMTESTTRACK"}
+
+	mockService.EXPECT().GetOrderByTrackNumber(gomock.Any(), "WBILMTESTTRACK").Return(expectedOrder, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/order/by-track/WBILMTESTTRACK", nil)
+	resp, _ := app.Test(req)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var order dto.Order
+	err := json.NewDecoder(resp.Body).Decode(&order)
+	assert.NoError(t, err)
+	assert.Equal(t, expectedOrder.OrderUID, order.OrderUID)
+}
+
+func TestHandler_GetOrderByTrackHandler_NotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	log := slogdiscard.NewDiscardLogger()
+	mockService := httpmock.NewMockOrderService(ctrl)
+	h := NewHandler(log, mockService, httpmock.NewMockValidator(ctrl), httpmock.NewMockCacheAdmin(ctrl), httpmock.NewMockHealthChecker(ctrl), "")
+
+	app := fiber.New()
+	app.Get("/api/order/by-track/:track", h.GetOrderByTrackHandler)
+
+	mockService.EXPECT().GetOrderByTrackNumber(gomock.Any(), "UNKNOWN").Return(dto.Order{}, service.ErrOrderNotFound)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/order/by-track/UNKNOWN", nil)
+	resp, _ := app.Test(req)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
+
+func TestHandler_GetCustomerOrdersHandler_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	log := slogdiscard.NewDiscardLogger()
+	mockService := httpmock.NewMockOrderService(ctrl)
+	h := NewHandler(log, mockService, httpmock.NewMockValidator(ctrl), httpmock.NewMockCacheAdmin(ctrl), httpmock.NewMockHealthChecker(ctrl), "")
+
+	app := fiber.New()
+	app.Get("/api/customers/:id/orders", h.GetCustomerOrdersHandler)
+
+	expectedPage := dto.OrderPage{Orders: []dto.Order{{OrderUID: uuid.New().String(), CustomerID: "test"}}}
+
+	mockService.EXPECT().
+		GetCustomerOrders(gomock.Any(), "test", dto.OrderFilter{Limit: 10, Cursor: "abc"}).
+		Return(expectedPage, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/customers/test/orders?limit=10&cursor=abc", nil)
+	resp, _ := app.Test(req)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var page dto.OrderPage
+	err := json.NewDecoder(resp.Body).Decode(&page)
+	assert.NoError(t, err)
+	assert.Equal(t, expectedPage, page)
+}
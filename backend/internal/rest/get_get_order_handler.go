@@ -15,9 +15,12 @@ import (
 // @Failure 500 {object} ErrorResp "internal server error"
 // @Router /api/order/{id} [get]
 func (h *Handler) GetOrderHandler(ctx *fiber.Ctx) error {
+	span := startSpan(ctx, "GetOrderHandler")
+	defer endSpan(ctx, span)
+
 	orderId := ctx.Params("id")
 
-	order, err := h.s.GetOrder(ctx.Context(), orderId)
+	order, err := h.s.GetOrder(ctx.UserContext(), orderId)
 	if err != nil {
 		if errors.Is(err, service.ErrOrderNotFound) {
 			return ctx.Status(fiber.StatusNotFound).JSON(
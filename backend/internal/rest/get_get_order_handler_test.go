@@ -22,7 +22,7 @@ func TestHandler_GetOrderHandler_Success(t *testing.T) {
 
 	log := slogdiscard.NewDiscardLogger()
 	mockService := httpmock.NewMockOrderService(ctrl)
-	h := NewHandler(log, mockService)
+	h := NewHandler(log, mockService, httpmock.NewMockValidator(ctrl), httpmock.NewMockCacheAdmin(ctrl), httpmock.NewMockHealthChecker(ctrl), "")
 
 	app := fiber.New()
 	app.Get("/api/order/:id", h.GetOrderHandler)
@@ -48,7 +48,7 @@ func TestHandler_GetOrderHandler_NotFound(t *testing.T) {
 
 	log := slogdiscard.NewDiscardLogger()
 	mockService := httpmock.NewMockOrderService(ctrl)
-	h := NewHandler(log, mockService)
+	h := NewHandler(log, mockService, httpmock.NewMockValidator(ctrl), httpmock.NewMockCacheAdmin(ctrl), httpmock.NewMockHealthChecker(ctrl), "")
 
 	app := fiber.New()
 	app.Get("/api/order/:id", h.GetOrderHandler)
@@ -68,7 +68,7 @@ func TestHandler_GetOrderHandler_InvalidUUID(t *testing.T) {
 
 	log := slogdiscard.NewDiscardLogger()
 	mockService := httpmock.NewMockOrderService(ctrl)
-	h := NewHandler(log, mockService)
+	h := NewHandler(log, mockService, httpmock.NewMockValidator(ctrl), httpmock.NewMockCacheAdmin(ctrl), httpmock.NewMockHealthChecker(ctrl), "")
 
 	app := fiber.New()
 	app.Get("/api/order/:id", h.GetOrderHandler)
@@ -88,7 +88,7 @@ func TestHandler_GetOrderHandler_InternalError(t *testing.T) {
 
 	log := slogdiscard.NewDiscardLogger()
 	mockService := httpmock.NewMockOrderService(ctrl)
-	h := NewHandler(log, mockService)
+	h := NewHandler(log, mockService, httpmock.NewMockValidator(ctrl), httpmock.NewMockCacheAdmin(ctrl), httpmock.NewMockHealthChecker(ctrl), "")
 
 	app := fiber.New()
 	app.Get("/api/order/:id", h.GetOrderHandler)
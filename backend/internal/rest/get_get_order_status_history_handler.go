@@ -0,0 +1,36 @@
+package rest
+
+import (
+	"errors"
+	"github.com/gofiber/fiber/v2"
+	"github.com/ilam072/wbtech-l0/backend/internal/service"
+	"github.com/ilam072/wbtech-l0/backend/pkg/logger/sl"
+)
+
+// @Summary Get order status history
+// @Description Returns the status changes of an order, oldest first
+// @Tags order
+// @Param id path string true "order uid"
+// @Success 200 {array} dto.StatusChange
+// @Failure 400 {object} ErrorResp "invalid uuid"
+// @Failure 404 {object} ErrorResp "order not found"
+// @Failure 500 {object} ErrorResp "internal server error"
+// @Router /api/order/{id}/history [get]
+func (h *Handler) GetOrderStatusHistoryHandler(ctx *fiber.Ctx) error {
+	history, err := h.s.GetOrderStatusHistory(ctx.UserContext(), ctx.Params("id"))
+	if err != nil {
+		if errors.Is(err, service.ErrOrderNotFound) {
+			return ctx.Status(fiber.StatusNotFound).JSON(
+				errorResponse("order not found"))
+		}
+		if errors.Is(err, service.ErrInvalidUUID) {
+			return ctx.Status(fiber.StatusBadRequest).JSON(
+				errorResponse("invalid uuid"))
+		}
+		h.log.Error("failed to get order status history", sl.Err(err))
+		return ctx.Status(fiber.StatusInternalServerError).JSON(
+			errorResponse("something went wrong, try again later"))
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(history)
+}
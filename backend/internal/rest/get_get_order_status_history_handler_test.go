@@ -0,0 +1,41 @@
+package rest
+
+import (
+	"encoding/json"
+	"github.com/google/uuid"
+	"github.com/ilam072/wbtech-l0/backend/internal/service"
+	"github.com/ilam072/wbtech-l0/backend/internal/types/dto"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHandler_GetOrderStatusHistoryHandler(t *testing.T) {
+	h, mockService := newStatusHandler(t)
+
+	orderID := uuid.New().String()
+	expected := []dto.StatusChange{{From: "created", To: "paid"}}
+
+	mockService.EXPECT().GetOrderStatusHistory(gomock.Any(), orderID).Return(expected, nil)
+
+	resp, err := h.api.Test(httptest.NewRequest(http.MethodGet, "/api/order/"+orderID+"/history", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var history []dto.StatusChange
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&history))
+	assert.Equal(t, expected, history)
+}
+
+func TestHandler_GetOrderStatusHistoryHandler_NotFound(t *testing.T) {
+	h, mockService := newStatusHandler(t)
+
+	orderID := uuid.New().String()
+	mockService.EXPECT().GetOrderStatusHistory(gomock.Any(), orderID).Return(nil, service.ErrOrderNotFound)
+
+	resp, err := h.api.Test(httptest.NewRequest(http.MethodGet, "/api/order/"+orderID+"/history", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
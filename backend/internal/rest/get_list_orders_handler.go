@@ -0,0 +1,47 @@
+package rest
+
+import (
+	"errors"
+	"github.com/gofiber/fiber/v2"
+	"github.com/ilam072/wbtech-l0/backend/internal/service"
+	"github.com/ilam072/wbtech-l0/backend/internal/types/dto"
+	"github.com/ilam072/wbtech-l0/backend/pkg/logger/sl"
+)
+
+// @Summary List orders
+// @Description Returns orders matching the filters, newest first. Use next_cursor from the response to fetch the next page.
+// @Tags order
+// @Param customer_id query string false "customer id"
+// @Param track_number query string false "track number"
+// @Param delivery_service query string false "delivery service"
+// @Param created_from query string false "created at or after (RFC 3339)"
+// @Param created_to query string false "created at or before (RFC 3339)"
+// @Param currency query string false "payment currency"
+// @Param provider query string false "payment provider"
+// @Param brand query string false "brand of at least one item"
+// @Param limit query int false "page size (1-100, default 20)"
+// @Param cursor query string false "next_cursor of the previous page"
+// @Success 200 {object} dto.OrderPage
+// @Failure 400 {object} ErrorResp "invalid filter"
+// @Failure 500 {object} ErrorResp "internal server error"
+// @Router /api/orders [get]
+func (h *Handler) ListOrdersHandler(ctx *fiber.Ctx) error {
+	var filter dto.OrderFilter
+	if err := ctx.QueryParser(&filter); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(
+			errorResponse("invalid query parameters"))
+	}
+
+	page, err := h.s.ListOrders(ctx.UserContext(), filter)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidFilter) {
+			return ctx.Status(fiber.StatusBadRequest).JSON(
+				errorResponse(reason(err, service.ErrInvalidFilter)))
+		}
+		h.log.Error("failed to list orders", sl.Err(err))
+		return ctx.Status(fiber.StatusInternalServerError).JSON(
+			errorResponse("something went wrong, try again later"))
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(page)
+}
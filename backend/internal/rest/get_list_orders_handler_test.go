@@ -0,0 +1,94 @@
+package rest
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/ilam072/wbtech-l0/backend/internal/service"
+	"github.com/ilam072/wbtech-l0/backend/internal/types/dto"
+	httpmock "github.com/ilam072/wbtech-l0/backend/mocks/http"
+	"github.com/ilam072/wbtech-l0/backend/pkg/logger/handlers/slogdiscard"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHandler_ListOrdersHandler_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	log := slogdiscard.NewDiscardLogger()
+	mockService := httpmock.NewMockOrderService(ctrl)
+	h := NewHandler(log, mockService, httpmock.NewMockValidator(ctrl), httpmock.NewMockCacheAdmin(ctrl), httpmock.NewMockHealthChecker(ctrl), "")
+
+	app := fiber.New()
+	app.Get("/api/orders", h.ListOrdersHandler)
+
+	expectedFilter := dto.OrderFilter{
+		CustomerID:  "test",
+		CreatedFrom: "2025-01-01T00:00:00Z",
+		Brand:       "Vivienne Sabo",
+		Currency:    "USD",
+		Limit:       2,
+		Cursor:      "abc",
+	}
+	expectedPage := dto.OrderPage{
+		Orders:     []dto.Order{{OrderUID: uuid.New().String()}, {OrderUID: uuid.New().String()}},
+		NextCursor: "def",
+	}
+
+	mockService.EXPECT().ListOrders(gomock.Any(), expectedFilter).Return(expectedPage, nil)
+
+	req := httptest.NewRequest(http.MethodGet,
+		"/api/orders?customer_id=test&created_from=2025-01-01T00:00:00Z&brand=Vivienne%20Sabo&currency=USD&limit=2&cursor=abc", nil)
+	resp, _ := app.Test(req)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var page dto.OrderPage
+	err := json.NewDecoder(resp.Body).Decode(&page)
+	assert.NoError(t, err)
+	assert.Equal(t, expectedPage, page)
+}
+
+func TestHandler_ListOrdersHandler_InvalidFilter(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	log := slogdiscard.NewDiscardLogger()
+	mockService := httpmock.NewMockOrderService(ctrl)
+	h := NewHandler(log, mockService, httpmock.NewMockValidator(ctrl), httpmock.NewMockCacheAdmin(ctrl), httpmock.NewMockHealthChecker(ctrl), "")
+
+	app := fiber.New()
+	app.Get("/api/orders", h.ListOrdersHandler)
+
+	mockService.EXPECT().ListOrders(gomock.Any(), dto.OrderFilter{Limit: 1000}).
+		Return(dto.OrderPage{}, fmt.Errorf("OrderService.ListOrders(): %w: limit must be between 1 and 100", service.ErrInvalidFilter))
+
+	req := httptest.NewRequest(http.MethodGet, "/api/orders?limit=1000", nil)
+	resp, _ := app.Test(req)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	var body ErrorResp
+	err := json.NewDecoder(resp.Body).Decode(&body)
+	assert.NoError(t, err)
+	assert.Equal(t, "invalid filter: limit must be between 1 and 100", body.Message)
+}
+
+func TestHandler_ListOrdersHandler_MalformedQuery(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	log := slogdiscard.NewDiscardLogger()
+	mockService := httpmock.NewMockOrderService(ctrl)
+	h := NewHandler(log, mockService, httpmock.NewMockValidator(ctrl), httpmock.NewMockCacheAdmin(ctrl), httpmock.NewMockHealthChecker(ctrl), "")
+
+	app := fiber.New()
+	app.Get("/api/orders", h.ListOrdersHandler)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/orders?limit=ten", nil)
+	resp, _ := app.Test(req)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
@@ -3,28 +3,75 @@ package rest
 import (
 	"context"
 	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/adaptor"
 	"github.com/gofiber/fiber/v2/middleware/cors"
 	"github.com/gofiber/swagger"
+	"github.com/ilam072/wbtech-l0/backend/internal/health"
+	"github.com/ilam072/wbtech-l0/backend/internal/metrics"
+	"github.com/ilam072/wbtech-l0/backend/internal/service"
 	"github.com/ilam072/wbtech-l0/backend/internal/types/dto"
 	_ "github.com/ilam072/wbtech-l0/docs"
 	"log/slog"
+	"time"
 )
 
 //go:generate mockgen -source=handler.go -destination=../../mocks/http/mock_handler.go -package http
 type OrderService interface {
 	GetOrder(ctx context.Context, orderId string) (dto.Order, error)
+	GetOrderByTrackNumber(ctx context.Context, trackNumber string) (dto.Order, error)
+	ListOrders(ctx context.Context, filter dto.OrderFilter) (dto.OrderPage, error)
+	GetCustomerOrders(ctx context.Context, customerID string, filter dto.OrderFilter) (dto.OrderPage, error)
+	CreateOrder(ctx context.Context, order dto.Order) error
+	CreateOrders(ctx context.Context, orders []dto.Order) ([]service.CreateResult, error)
+	UpsertOrder(ctx context.Context, order dto.Order) (service.UpsertOutcome, error)
+	UpdateOrderStatus(ctx context.Context, update dto.StatusUpdate) (dto.Order, error)
+	GetOrderStatusHistory(ctx context.Context, orderId string) ([]dto.StatusChange, error)
+	CancelOrder(ctx context.Context, orderId string, reason string) (dto.Order, error)
+	RefundOrder(ctx context.Context, request dto.RefundRequest) (dto.Order, error)
+}
+
+type Validator interface {
+	Validate(i interface{}) error
+}
+
+type HealthChecker interface {
+	Ready(ctx context.Context) health.Report
+}
+
+type CacheAdmin interface {
+	Stats() dto.CacheStats
+	Invalidate(key string) bool
+	Purge()
+	Preload(ctx context.Context, limit int) error
 }
 
 type Handler struct {
 	log *slog.Logger
 	api *fiber.App
 	s   OrderService
+	v   Validator
+	c   CacheAdmin
+	hc  HealthChecker
+
+	adminToken string
 }
 
-func NewHandler(log *slog.Logger, s OrderService) *Handler {
+// NewHandler registers the public API routes and, when adminToken is not
+// empty, the cache admin routes guarded by it.
+func NewHandler(
+	log *slog.Logger,
+	s OrderService,
+	v Validator,
+	c CacheAdmin,
+	hc HealthChecker,
+	adminToken string,
+) *Handler {
 	api := fiber.New()
 
+	api.Use(observeRequest)
+
 	api.Get("/swagger/*", swagger.HandlerDefault)
+	api.Get("/metrics", adaptor.HTTPHandler(metrics.Handler()))
 
 	api.Use(cors.New(cors.Config{
 		AllowOrigins: "*",
@@ -34,8 +81,33 @@ func NewHandler(log *slog.Logger, s OrderService) *Handler {
 		log: log,
 		api: api,
 		s:   s,
+		v:   v,
+		c:   c,
+		hc:  hc,
+
+		adminToken: adminToken,
 	}
+	h.api.Get("/healthz", h.LivenessHandler)
+	h.api.Get("/readyz", h.ReadinessHandler)
 	h.api.Get("/api/order/:id", h.GetOrderHandler)
+	h.api.Get("/api/order/by-track/:track", h.GetOrderByTrackHandler)
+	h.api.Patch("/api/order/:id/status", h.UpdateOrderStatusHandler)
+	h.api.Get("/api/order/:id/history", h.GetOrderStatusHistoryHandler)
+	h.api.Post("/api/order/:id/cancel", h.CancelOrderHandler)
+	h.api.Post("/api/order/:id/refunds", h.CreateRefundHandler)
+	h.api.Get("/api/orders", h.ListOrdersHandler)
+	h.api.Get("/api/customers/:id/orders", h.GetCustomerOrdersHandler)
+	h.api.Post("/api/orders", h.CreateOrderHandler)
+	h.api.Put("/api/orders", h.UpsertOrderHandler)
+	h.api.Post("/api/orders/batch", h.CreateOrdersHandler)
+
+	if adminToken != "" {
+		admin := h.api.Group("/api/admin", h.adminAuth)
+		admin.Get("/cache", h.CacheStatsHandler)
+		admin.Delete("/cache", h.PurgeCacheHandler)
+		admin.Delete("/cache/orders/:id", h.EvictOrderHandler)
+		admin.Post("/cache/preload", h.PreloadCacheHandler)
+	}
 
 	return h
 }
@@ -44,6 +116,8 @@ func (h *Handler) Listen(addr string) error {
 	return h.api.Listen(addr)
 }
 
-func (h *Handler) Shutdown() error {
-	return h.api.Shutdown()
+// Shutdown stops accepting connections and waits up to timeout for active
+// requests to complete before closing their connections.
+func (h *Handler) Shutdown(timeout time.Duration) error {
+	return h.api.ShutdownWithTimeout(timeout)
 }
@@ -0,0 +1,31 @@
+package rest
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/ilam072/wbtech-l0/backend/internal/health"
+)
+
+// @Summary Liveness probe
+// @Description Reports that the process is up
+// @Tags health
+// @Success 200 {object} health.Report
+// @Router /healthz [get]
+func (h *Handler) LivenessHandler(ctx *fiber.Ctx) error {
+	return ctx.Status(fiber.StatusOK).JSON(health.Report{Status: health.StatusOK})
+}
+
+// @Summary Readiness probe
+// @Description Checks Postgres, Kafka and cache warm-up and reports the state of each dependency
+// @Tags health
+// @Success 200 {object} health.Report
+// @Failure 503 {object} health.Report "some dependency is unavailable"
+// @Router /readyz [get]
+func (h *Handler) ReadinessHandler(ctx *fiber.Ctx) error {
+	report := h.hc.Ready(ctx.UserContext())
+
+	status := fiber.StatusOK
+	if report.Status != health.StatusOK {
+		status = fiber.StatusServiceUnavailable
+	}
+	return ctx.Status(status).JSON(report)
+}
@@ -0,0 +1,69 @@
+package rest
+
+import (
+	"encoding/json"
+	"github.com/ilam072/wbtech-l0/backend/internal/health"
+	httpmock "github.com/ilam072/wbtech-l0/backend/mocks/http"
+	"github.com/ilam072/wbtech-l0/backend/pkg/logger/handlers/slogdiscard"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func newHealthHandler(t *testing.T) (*Handler, *httpmock.MockHealthChecker) {
+	ctrl := gomock.NewController(t)
+	t.Cleanup(ctrl.Finish)
+
+	checker := httpmock.NewMockHealthChecker(ctrl)
+	h := NewHandler(
+		slogdiscard.NewDiscardLogger(),
+		httpmock.NewMockOrderService(ctrl),
+		httpmock.NewMockValidator(ctrl),
+		httpmock.NewMockCacheAdmin(ctrl),
+		checker,
+		"",
+	)
+	return h, checker
+}
+
+func TestHandler_LivenessHandler(t *testing.T) {
+	h, _ := newHealthHandler(t)
+
+	resp, _ := h.api.Test(httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+}
+
+func TestHandler_ReadinessHandler_Ready(t *testing.T) {
+	h, checker := newHealthHandler(t)
+
+	checker.EXPECT().Ready(gomock.Any()).Return(health.Report{
+		Status: health.StatusOK,
+		Checks: map[string]health.CheckResult{"postgres": {Status: health.StatusOK}},
+	})
+
+	resp, _ := h.api.Test(httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+}
+
+func TestHandler_ReadinessHandler_Unavailable(t *testing.T) {
+	h, checker := newHealthHandler(t)
+
+	expected := health.Report{
+		Status: health.StatusUnavailable,
+		Checks: map[string]health.CheckResult{
+			"postgres": {Status: health.StatusOK},
+			"cache":    {Status: health.StatusUnavailable, Error: health.ErrNotReady.Error()},
+		},
+	}
+	checker.EXPECT().Ready(gomock.Any()).Return(expected)
+
+	resp, _ := h.api.Test(httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+
+	var report health.Report
+	err := json.NewDecoder(resp.Body).Decode(&report)
+	assert.NoError(t, err)
+	assert.Equal(t, expected, report)
+}
@@ -0,0 +1,29 @@
+package rest
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/ilam072/wbtech-l0/backend/internal/metrics"
+	"strconv"
+	"time"
+)
+
+// observeRequest records the request duration labelled with the route
+// pattern rather than the raw path to keep the label set bounded.
+func observeRequest(ctx *fiber.Ctx) error {
+	start := time.Now()
+
+	err := ctx.Next()
+
+	status := ctx.Response().StatusCode()
+	if fe, ok := err.(*fiber.Error); ok {
+		status = fe.Code
+	} else if err != nil {
+		status = fiber.StatusInternalServerError
+	}
+
+	metrics.HTTPRequestDuration.
+		WithLabelValues(ctx.Method(), ctx.Route().Path, strconv.Itoa(status)).
+		Observe(time.Since(start).Seconds())
+
+	return err
+}
@@ -0,0 +1,42 @@
+package rest
+
+import (
+	"github.com/ilam072/wbtech-l0/backend/internal/service"
+	"github.com/ilam072/wbtech-l0/backend/internal/types/dto"
+	httpmock "github.com/ilam072/wbtech-l0/backend/mocks/http"
+	"github.com/ilam072/wbtech-l0/backend/pkg/logger/handlers/slogdiscard"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHandler_Metrics(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := httpmock.NewMockOrderService(ctrl)
+	h := NewHandler(
+		slogdiscard.NewDiscardLogger(),
+		mockService,
+		httpmock.NewMockValidator(ctrl),
+		httpmock.NewMockCacheAdmin(ctrl),
+		httpmock.NewMockHealthChecker(ctrl),
+		"",
+	)
+
+	mockService.EXPECT().GetOrder(gomock.Any(), "missing").Return(dto.Order{}, service.ErrOrderNotFound)
+
+	resp, _ := h.api.Test(httptest.NewRequest(http.MethodGet, "/api/order/missing", nil))
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+	resp, _ = h.api.Test(httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	body, err := io.ReadAll(resp.Body)
+	assert.NoError(t, err)
+	assert.Contains(t, string(body),
+		`orders_http_request_duration_seconds_count{method="GET",route="/api/order/:id",status="404"}`)
+}
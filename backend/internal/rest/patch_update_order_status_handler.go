@@ -0,0 +1,70 @@
+package rest
+
+import (
+	"errors"
+	"github.com/gofiber/fiber/v2"
+	"github.com/ilam072/wbtech-l0/backend/internal/service"
+	"github.com/ilam072/wbtech-l0/backend/internal/types/dto"
+	"github.com/ilam072/wbtech-l0/backend/pkg/logger/sl"
+)
+
+type StatusUpdateReq struct {
+	Status string `json:"status" example:"paid"`
+	Reason string `json:"reason,omitempty"`
+}
+
+// @Summary Update order status
+// @Description Moves the order to another lifecycle status: created -> paid -> assembled -> shipped -> delivered,
+// @Description cancelled before shipping, returned after shipping. Repeating the current status is a no-op.
+// @Tags order
+// @Accept json
+// @Produce json
+// @Param id path string true "order uid"
+// @Param update body StatusUpdateReq true "new status"
+// @Success 200 {object} dto.Order
+// @Failure 400 {object} ErrorResp "malformed body or unknown status"
+// @Failure 404 {object} ErrorResp "order not found"
+// @Failure 409 {object} ErrorResp "transition not allowed or status changed concurrently"
+// @Failure 422 {object} ValidationErrorResp "validation failed"
+// @Failure 500 {object} ErrorResp "internal server error"
+// @Router /api/order/{id}/status [patch]
+func (h *Handler) UpdateOrderStatusHandler(ctx *fiber.Ctx) error {
+	var req StatusUpdateReq
+	if err := ctx.BodyParser(&req); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(
+			errorResponse("malformed status body"))
+	}
+
+	update := dto.StatusUpdate{
+		OrderUID: ctx.Params("id"),
+		Status:   req.Status,
+		Reason:   req.Reason,
+	}
+	if err := h.v.Validate(update); err != nil {
+		return ctx.Status(fiber.StatusUnprocessableEntity).JSON(
+			validationErrorResponse(err))
+	}
+
+	order, err := h.s.UpdateOrderStatus(ctx.UserContext(), update)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrOrderNotFound):
+			return ctx.Status(fiber.StatusNotFound).JSON(
+				errorResponse("order not found"))
+		case errors.Is(err, service.ErrInvalidStatus):
+			return ctx.Status(fiber.StatusBadRequest).JSON(
+				errorResponse("unknown order status"))
+		case errors.Is(err, service.ErrInvalidTransition):
+			return ctx.Status(fiber.StatusConflict).JSON(
+				errorResponse(reason(err, service.ErrInvalidTransition)))
+		case errors.Is(err, service.ErrStatusConflict), errors.Is(err, service.ErrRefundExceedsPayment):
+			return ctx.Status(fiber.StatusConflict).JSON(
+				errorResponse("order status changed concurrently, try again"))
+		}
+		h.log.Error("failed to update order status", sl.Err(err))
+		return ctx.Status(fiber.StatusInternalServerError).JSON(
+			errorResponse("something went wrong, try again later"))
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(order)
+}
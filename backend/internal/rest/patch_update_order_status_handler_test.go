@@ -0,0 +1,111 @@
+package rest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/ilam072/wbtech-l0/backend/internal/config"
+	"github.com/ilam072/wbtech-l0/backend/internal/service"
+	"github.com/ilam072/wbtech-l0/backend/internal/types/dto"
+	"github.com/ilam072/wbtech-l0/backend/internal/validator"
+	httpmock "github.com/ilam072/wbtech-l0/backend/mocks/http"
+	"github.com/ilam072/wbtech-l0/backend/pkg/logger/handlers/slogdiscard"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func newStatusHandler(t *testing.T) (*Handler, *httpmock.MockOrderService) {
+	ctrl := gomock.NewController(t)
+	t.Cleanup(ctrl.Finish)
+
+	log := slogdiscard.NewDiscardLogger()
+	mockService := httpmock.NewMockOrderService(ctrl)
+	h := NewHandler(log, mockService, validator.New(log, config.ValidationConfig{}), httpmock.NewMockCacheAdmin(ctrl), httpmock.NewMockHealthChecker(ctrl), "")
+	return h, mockService
+}
+
+func patchStatus(t *testing.T, h *Handler, orderID string, body interface{}) *http.Response {
+	t.Helper()
+
+	payload, err := json.Marshal(body)
+	assert.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodPatch, "/api/order/"+orderID+"/status", bytes.NewReader(payload))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := h.api.Test(req)
+	assert.NoError(t, err)
+	return resp
+}
+
+func TestHandler_UpdateOrderStatusHandler_Success(t *testing.T) {
+	h, mockService := newStatusHandler(t)
+
+	orderID := uuid.New().String()
+	expected := dto.Order{OrderUID: orderID, Status: "paid"}
+
+	mockService.EXPECT().UpdateOrderStatus(gomock.Any(), dto.StatusUpdate{
+		OrderUID: orderID,
+		Status:   "paid",
+		Reason:   "payment confirmed",
+	}).Return(expected, nil)
+
+	resp := patchStatus(t, h, orderID, StatusUpdateReq{Status: "paid", Reason: "payment confirmed"})
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var order dto.Order
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&order))
+	assert.Equal(t, expected, order)
+}
+
+func TestHandler_UpdateOrderStatusHandler_ValidationFailed(t *testing.T) {
+	h, mockService := newStatusHandler(t)
+
+	mockService.EXPECT().UpdateOrderStatus(gomock.Any(), gomock.Any()).Times(0)
+
+	resp := patchStatus(t, h, uuid.New().String(), StatusUpdateReq{})
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.StatusCode)
+
+	var body ValidationErrorResp
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	assert.Len(t, body.Errors, 1)
+	assert.Equal(t, "status", body.Errors[0].Field)
+}
+
+func TestHandler_UpdateOrderStatusHandler_Errors(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		statusCode int
+		message    string
+	}{
+		{"not found", service.ErrOrderNotFound, http.StatusNotFound, "order not found"},
+		{"unknown status", fmt.Errorf("op: %w: %q", service.ErrInvalidStatus, "lost"), http.StatusBadRequest, "unknown order status"},
+		{
+			"transition not allowed",
+			fmt.Errorf("op: %w: delivered -> paid", service.ErrInvalidTransition),
+			http.StatusConflict,
+			"order status transition not allowed: delivered -> paid",
+		},
+		{"concurrent change", service.ErrStatusConflict, http.StatusConflict, "order status changed concurrently, try again"},
+		{"internal error", fmt.Errorf("db is down"), http.StatusInternalServerError, "something went wrong, try again later"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, mockService := newStatusHandler(t)
+
+			mockService.EXPECT().UpdateOrderStatus(gomock.Any(), gomock.Any()).Return(dto.Order{}, tt.err)
+
+			resp := patchStatus(t, h, uuid.New().String(), StatusUpdateReq{Status: "paid"})
+			assert.Equal(t, tt.statusCode, resp.StatusCode)
+
+			var body ErrorResp
+			assert.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+			assert.Equal(t, tt.message, body.Message)
+		})
+	}
+}
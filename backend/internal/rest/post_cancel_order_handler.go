@@ -0,0 +1,59 @@
+package rest
+
+import (
+	"errors"
+	"github.com/gofiber/fiber/v2"
+	"github.com/ilam072/wbtech-l0/backend/internal/service"
+	"github.com/ilam072/wbtech-l0/backend/pkg/logger/sl"
+)
+
+type CancelReq struct {
+	Reason string `json:"reason,omitempty" example:"customer request"`
+}
+
+// @Summary Cancel order
+// @Description Cancels an order that has not been shipped yet. Paid orders get the amount not refunded yet
+// @Description refunded in the same step. Cancelling a cancelled order is a no-op.
+// @Tags order
+// @Accept json
+// @Produce json
+// @Param id path string true "order uid"
+// @Param cancel body CancelReq false "cancellation reason"
+// @Success 200 {object} dto.Order
+// @Failure 400 {object} ErrorResp "malformed body or invalid uuid"
+// @Failure 404 {object} ErrorResp "order not found"
+// @Failure 409 {object} ErrorResp "order cannot be cancelled or status changed concurrently"
+// @Failure 500 {object} ErrorResp "internal server error"
+// @Router /api/order/{id}/cancel [post]
+func (h *Handler) CancelOrderHandler(ctx *fiber.Ctx) error {
+	var req CancelReq
+	if len(ctx.Body()) > 0 {
+		if err := ctx.BodyParser(&req); err != nil {
+			return ctx.Status(fiber.StatusBadRequest).JSON(
+				errorResponse("malformed cancel body"))
+		}
+	}
+
+	order, err := h.s.CancelOrder(ctx.UserContext(), ctx.Params("id"), req.Reason)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidUUID):
+			return ctx.Status(fiber.StatusBadRequest).JSON(
+				errorResponse("invalid uuid"))
+		case errors.Is(err, service.ErrOrderNotFound):
+			return ctx.Status(fiber.StatusNotFound).JSON(
+				errorResponse("order not found"))
+		case errors.Is(err, service.ErrInvalidTransition):
+			return ctx.Status(fiber.StatusConflict).JSON(
+				errorResponse(reason(err, service.ErrInvalidTransition)))
+		case errors.Is(err, service.ErrStatusConflict), errors.Is(err, service.ErrRefundExceedsPayment):
+			return ctx.Status(fiber.StatusConflict).JSON(
+				errorResponse("order changed concurrently, try again"))
+		}
+		h.log.Error("failed to cancel order", sl.Err(err))
+		return ctx.Status(fiber.StatusInternalServerError).JSON(
+			errorResponse("something went wrong, try again later"))
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(order)
+}
@@ -0,0 +1,89 @@
+package rest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/ilam072/wbtech-l0/backend/internal/service"
+	"github.com/ilam072/wbtech-l0/backend/internal/types/dto"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func postCancel(t *testing.T, h *Handler, orderID string, body []byte) *http.Response {
+	t.Helper()
+
+	req := httptest.NewRequest(http.MethodPost, "/api/order/"+orderID+"/cancel", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := h.api.Test(req)
+	assert.NoError(t, err)
+	return resp
+}
+
+func TestHandler_CancelOrderHandler_Success(t *testing.T) {
+	h, mockService := newStatusHandler(t)
+
+	orderID := uuid.New().String()
+	expected := dto.Order{OrderUID: orderID, Status: "cancelled", RefundedAmount: 1917}
+
+	mockService.EXPECT().CancelOrder(gomock.Any(), orderID, "customer request").Return(expected, nil)
+
+	payload, err := json.Marshal(CancelReq{Reason: "customer request"})
+	assert.NoError(t, err)
+
+	resp := postCancel(t, h, orderID, payload)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var order dto.Order
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&order))
+	assert.Equal(t, expected, order)
+}
+
+func TestHandler_CancelOrderHandler_EmptyBody(t *testing.T) {
+	h, mockService := newStatusHandler(t)
+
+	orderID := uuid.New().String()
+	mockService.EXPECT().CancelOrder(gomock.Any(), orderID, "").Return(dto.Order{OrderUID: orderID}, nil)
+
+	resp := postCancel(t, h, orderID, nil)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+}
+
+func TestHandler_CancelOrderHandler_Errors(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		statusCode int
+		message    string
+	}{
+		{"invalid uuid", service.ErrInvalidUUID, http.StatusBadRequest, "invalid uuid"},
+		{"not found", service.ErrOrderNotFound, http.StatusNotFound, "order not found"},
+		{
+			"transition not allowed",
+			fmt.Errorf("op: %w: shipped -> cancelled", service.ErrInvalidTransition),
+			http.StatusConflict,
+			"order status transition not allowed: shipped -> cancelled",
+		},
+		{"concurrent change", service.ErrStatusConflict, http.StatusConflict, "order changed concurrently, try again"},
+		{"internal error", fmt.Errorf("db is down"), http.StatusInternalServerError, "something went wrong, try again later"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, mockService := newStatusHandler(t)
+
+			mockService.EXPECT().CancelOrder(gomock.Any(), gomock.Any(), gomock.Any()).Return(dto.Order{}, tt.err)
+
+			resp := postCancel(t, h, uuid.New().String(), nil)
+			assert.Equal(t, tt.statusCode, resp.StatusCode)
+
+			var body ErrorResp
+			assert.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+			assert.Equal(t, tt.message, body.Message)
+		})
+	}
+}
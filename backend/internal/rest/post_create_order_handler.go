@@ -0,0 +1,47 @@
+package rest
+
+import (
+	"errors"
+	"github.com/gofiber/fiber/v2"
+	"github.com/ilam072/wbtech-l0/backend/internal/service"
+	"github.com/ilam072/wbtech-l0/backend/internal/types/dto"
+	"github.com/ilam072/wbtech-l0/backend/pkg/logger/sl"
+	"log/slog"
+)
+
+// @Summary Create order
+// @Description Validates and stores a single order, the same way orders from Kafka are stored
+// @Tags order
+// @Accept json
+// @Produce json
+// @Param order body dto.Order true "order"
+// @Success 201 {object} CreatedResp
+// @Failure 400 {object} ErrorResp "malformed body"
+// @Failure 409 {object} ErrorResp "order already exists"
+// @Failure 422 {object} ValidationErrorResp "validation failed"
+// @Failure 500 {object} ErrorResp "internal server error"
+// @Router /api/orders [post]
+func (h *Handler) CreateOrderHandler(ctx *fiber.Ctx) error {
+	var order dto.Order
+	if err := ctx.BodyParser(&order); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(
+			errorResponse("malformed order body"))
+	}
+
+	if err := h.v.Validate(order); err != nil {
+		return ctx.Status(fiber.StatusUnprocessableEntity).JSON(
+			validationErrorResponse(err))
+	}
+
+	if err := h.s.CreateOrder(ctx.UserContext(), order); err != nil {
+		if errors.Is(err, service.ErrOrderExists) {
+			return ctx.Status(fiber.StatusConflict).JSON(
+				errorResponse("order already exists"))
+		}
+		h.log.Error("failed to create order", slog.String("order_uid", order.OrderUID), sl.Err(err))
+		return ctx.Status(fiber.StatusInternalServerError).JSON(
+			errorResponse("something went wrong, try again later"))
+	}
+
+	return ctx.Status(fiber.StatusCreated).JSON(CreatedResp{OrderUID: order.OrderUID})
+}
@@ -0,0 +1,217 @@
+package rest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/ilam072/wbtech-l0/backend/internal/config"
+	"github.com/ilam072/wbtech-l0/backend/internal/service"
+	"github.com/ilam072/wbtech-l0/backend/internal/types/dto"
+	"github.com/ilam072/wbtech-l0/backend/internal/validator"
+	httpmock "github.com/ilam072/wbtech-l0/backend/mocks/http"
+	"github.com/ilam072/wbtech-l0/backend/pkg/logger/handlers/slogdiscard"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func validOrder() dto.Order {
+	return dto.Order{
+		OrderUID:    uuid.New().String(),
+		TrackNumber: "WBILMTESTTRACK",
+		Entry:       "WBIL",
+		Delivery: dto.Delivery{
+			Name:    "Test Testov",
+			Phone:   "+9720000000",
+			Zip:     "2639809",
+			City:    "Kiryat Mozkin",
+			Address: "Ploshad Mira 15",
+			Region:  "Kraiot",
+			Email:   "test@gmail.com",
+		},
+		Payment: dto.Payment{
+			Transaction:  "b563feb7b2b84b6test",
+			Currency:     "USD",
+			Provider:     "wbpay",
+			Amount:       1917,
+			PaymentDt:    1637907727,
+			Bank:         "alpha",
+			DeliveryCost: 1500,
+			GoodsTotal:   317,
+			CustomFee:    100,
+		},
+		Items: []dto.Item{{
+			ChrtID:      9934930,
+			TrackNumber: "WBILMTESTTRACK",
+			Price:       453,
+			Rid:         "ab4219087a764ae0btest",
+			Name:        "Mascaras",
+			Sale:        30,
+			Size:        "0",
+			TotalPrice:  317,
+			NmID:        2389212,
+			Brand:       "Vivienne Sabo",
+			Status:      202,
+		}},
+		Locale:          "en",
+		CustomerID:      "test",
+		DeliveryService: "meest",
+		Shardkey:        "9",
+		SmID:            99,
+		DateCreated:     time.Date(2021, 11, 26, 6, 22, 19, 0, time.UTC),
+		OofShard:        "1",
+	}
+}
+
+func postJSON(t *testing.T, app *fiber.App, path string, body interface{}) *http.Response {
+	t.Helper()
+
+	payload, err := json.Marshal(body)
+	assert.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodPost, path, bytes.NewReader(payload))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := app.Test(req)
+	assert.NoError(t, err)
+	return resp
+}
+
+func TestHandler_CreateOrderHandler_Created(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	log := slogdiscard.NewDiscardLogger()
+	mockService := httpmock.NewMockOrderService(ctrl)
+	h := NewHandler(log, mockService, validator.New(log, config.ValidationConfig{}), httpmock.NewMockCacheAdmin(ctrl), httpmock.NewMockHealthChecker(ctrl), "")
+
+	app := fiber.New()
+	app.Post("/api/orders", h.CreateOrderHandler)
+
+	order := validOrder()
+	mockService.EXPECT().CreateOrder(gomock.Any(), order).Return(nil)
+
+	resp := postJSON(t, app, "/api/orders", order)
+	assert.Equal(t, http.StatusCreated, resp.StatusCode)
+
+	var created CreatedResp
+	err := json.NewDecoder(resp.Body).Decode(&created)
+	assert.NoError(t, err)
+	assert.Equal(t, order.OrderUID, created.OrderUID)
+}
+
+func TestHandler_CreateOrderHandler_Conflict(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	log := slogdiscard.NewDiscardLogger()
+	mockService := httpmock.NewMockOrderService(ctrl)
+	h := NewHandler(log, mockService, validator.New(log, config.ValidationConfig{}), httpmock.NewMockCacheAdmin(ctrl), httpmock.NewMockHealthChecker(ctrl), "")
+
+	app := fiber.New()
+	app.Post("/api/orders", h.CreateOrderHandler)
+
+	order := validOrder()
+	mockService.EXPECT().CreateOrder(gomock.Any(), order).
+		Return(fmt.Errorf("OrderService.CreateOrder(): %w", service.ErrOrderExists))
+
+	resp := postJSON(t, app, "/api/orders", order)
+	assert.Equal(t, http.StatusConflict, resp.StatusCode)
+}
+
+func TestHandler_CreateOrderHandler_ValidationFailed(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	log := slogdiscard.NewDiscardLogger()
+	mockService := httpmock.NewMockOrderService(ctrl)
+	h := NewHandler(log, mockService, validator.New(log, config.ValidationConfig{}), httpmock.NewMockCacheAdmin(ctrl), httpmock.NewMockHealthChecker(ctrl), "")
+
+	app := fiber.New()
+	app.Post("/api/orders", h.CreateOrderHandler)
+
+	order := validOrder()
+	order.Delivery.Email = "not-an-email"
+
+	resp := postJSON(t, app, "/api/orders", order)
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.StatusCode)
+
+	var verr ValidationErrorResp
+	err := json.NewDecoder(resp.Body).Decode(&verr)
+	assert.NoError(t, err)
+	assert.Len(t, verr.Errors, 1)
+	assert.Equal(t, validator.FieldError{
+		Field:   "delivery.email",
+		Rule:    "email",
+		Message: "must be a valid email address",
+	}, verr.Errors[0])
+}
+
+func TestHandler_CreateOrderHandler_MalformedBody(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	log := slogdiscard.NewDiscardLogger()
+	mockService := httpmock.NewMockOrderService(ctrl)
+	h := NewHandler(log, mockService, validator.New(log, config.ValidationConfig{}), httpmock.NewMockCacheAdmin(ctrl), httpmock.NewMockHealthChecker(ctrl), "")
+
+	app := fiber.New()
+	app.Post("/api/orders", h.CreateOrderHandler)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/orders", bytes.NewReader([]byte(`{"order_uid":`)))
+	req.Header.Set("Content-Type", "application/json")
+	resp, _ := app.Test(req)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
+
+func TestHandler_CreateOrdersHandler_MixedOutcomes(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	log := slogdiscard.NewDiscardLogger()
+	mockService := httpmock.NewMockOrderService(ctrl)
+	h := NewHandler(log, mockService, validator.New(log, config.ValidationConfig{}), httpmock.NewMockCacheAdmin(ctrl), httpmock.NewMockHealthChecker(ctrl), "")
+
+	app := fiber.New()
+	app.Post("/api/orders/batch", h.CreateOrdersHandler)
+
+	created, duplicate, invalid := validOrder(), validOrder(), validOrder()
+	invalid.Items = nil
+
+	mockService.EXPECT().CreateOrders(gomock.Any(), []dto.Order{created, duplicate}).
+		Return([]service.CreateResult{
+			{OrderUID: created.OrderUID},
+			{OrderUID: duplicate.OrderUID, Err: service.ErrOrderExists},
+		}, nil)
+
+	resp := postJSON(t, app, "/api/orders/batch", []dto.Order{created, invalid, duplicate})
+	assert.Equal(t, http.StatusMultiStatus, resp.StatusCode)
+
+	var batch BatchResp
+	err := json.NewDecoder(resp.Body).Decode(&batch)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, batch.Created)
+	assert.Equal(t, 2, batch.Failed)
+	assert.Equal(t, http.StatusCreated, batch.Results[0].Status)
+	assert.Equal(t, http.StatusUnprocessableEntity, batch.Results[1].Status)
+	assert.Equal(t, http.StatusConflict, batch.Results[2].Status)
+}
+
+func TestHandler_CreateOrdersHandler_Empty(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	log := slogdiscard.NewDiscardLogger()
+	mockService := httpmock.NewMockOrderService(ctrl)
+	h := NewHandler(log, mockService, validator.New(log, config.ValidationConfig{}), httpmock.NewMockCacheAdmin(ctrl), httpmock.NewMockHealthChecker(ctrl), "")
+
+	app := fiber.New()
+	app.Post("/api/orders/batch", h.CreateOrdersHandler)
+
+	resp := postJSON(t, app, "/api/orders/batch", []dto.Order{})
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
@@ -0,0 +1,94 @@
+package rest
+
+import (
+	"errors"
+	"fmt"
+	"github.com/gofiber/fiber/v2"
+	"github.com/ilam072/wbtech-l0/backend/internal/service"
+	"github.com/ilam072/wbtech-l0/backend/internal/types/dto"
+	"github.com/ilam072/wbtech-l0/backend/pkg/logger/sl"
+	"log/slog"
+)
+
+const maxBatchSize = 500
+
+// @Summary Create orders in batch
+// @Description Validates and stores an array of orders and reports the outcome of each one.
+// @Description Responds with 201 when every order was stored and 207 otherwise.
+// @Tags order
+// @Accept json
+// @Produce json
+// @Param orders body []dto.Order true "orders"
+// @Success 201 {object} BatchResp
+// @Success 207 {object} BatchResp
+// @Failure 400 {object} ErrorResp "malformed body or batch size out of range"
+// @Failure 500 {object} ErrorResp "internal server error"
+// @Router /api/orders/batch [post]
+func (h *Handler) CreateOrdersHandler(ctx *fiber.Ctx) error {
+	var orders []dto.Order
+	if err := ctx.BodyParser(&orders); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(
+			errorResponse("malformed orders body"))
+	}
+	if len(orders) == 0 || len(orders) > maxBatchSize {
+		return ctx.Status(fiber.StatusBadRequest).JSON(
+			errorResponse(fmt.Sprintf("batch must contain from 1 to %d orders", maxBatchSize)))
+	}
+
+	resp := BatchResp{Results: make([]BatchItemResp, len(orders))}
+	valid := make([]dto.Order, 0, len(orders))
+	positions := make([]int, 0, len(orders))
+
+	for i, order := range orders {
+		resp.Results[i].OrderUID = order.OrderUID
+
+		if err := h.v.Validate(order); err != nil {
+			verr := validationErrorResponse(err)
+			resp.Results[i].Status = fiber.StatusUnprocessableEntity
+			resp.Results[i].Message = verr.Message
+			resp.Results[i].Errors = verr.Errors
+			continue
+		}
+
+		valid = append(valid, order)
+		positions = append(positions, i)
+	}
+
+	if len(valid) > 0 {
+		results, err := h.s.CreateOrders(ctx.UserContext(), valid)
+		if err != nil {
+			h.log.Error("failed to create orders", slog.Int("count", len(valid)), sl.Err(err))
+			return ctx.Status(fiber.StatusInternalServerError).JSON(
+				errorResponse("something went wrong, try again later"))
+		}
+
+		for j, result := range results {
+			item := &resp.Results[positions[j]]
+
+			switch {
+			case result.Err == nil:
+				item.Status = fiber.StatusCreated
+			case errors.Is(result.Err, service.ErrOrderExists):
+				item.Status = fiber.StatusConflict
+				item.Message = "order already exists"
+			default:
+				h.log.Error("failed to create order", slog.String("order_uid", result.OrderUID), sl.Err(result.Err))
+				item.Status = fiber.StatusInternalServerError
+				item.Message = "failed to store order"
+			}
+		}
+	}
+
+	for _, item := range resp.Results {
+		if item.Status == fiber.StatusCreated {
+			resp.Created++
+		} else {
+			resp.Failed++
+		}
+	}
+
+	if resp.Failed > 0 {
+		return ctx.Status(fiber.StatusMultiStatus).JSON(resp)
+	}
+	return ctx.Status(fiber.StatusCreated).JSON(resp)
+}
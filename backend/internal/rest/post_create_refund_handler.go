@@ -0,0 +1,79 @@
+package rest
+
+import (
+	"errors"
+	"github.com/gofiber/fiber/v2"
+	"github.com/ilam072/wbtech-l0/backend/internal/service"
+	"github.com/ilam072/wbtech-l0/backend/internal/types/dto"
+	"github.com/ilam072/wbtech-l0/backend/pkg/logger/sl"
+)
+
+type RefundReq struct {
+	ChrtIDs []int  `json:"chrt_ids,omitempty" example:"9934930"`
+	Reason  string `json:"reason" example:"damaged in delivery"`
+}
+
+// @Summary Refund order
+// @Description Refunds the listed items by their total price or, without chrt_ids, the whole amount not refunded yet.
+// @Description Only paid, assembled, shipped, delivered and returned orders can be refunded.
+// @Tags order
+// @Accept json
+// @Produce json
+// @Param id path string true "order uid"
+// @Param refund body RefundReq true "refund"
+// @Success 201 {object} dto.Order
+// @Failure 400 {object} ErrorResp "malformed body"
+// @Failure 404 {object} ErrorResp "order not found"
+// @Failure 409 {object} ErrorResp "order cannot be refunded, already refunded or changed concurrently"
+// @Failure 422 {object} ValidationErrorResp "validation failed, unknown or free item, or refund exceeds payment"
+// @Failure 500 {object} ErrorResp "internal server error"
+// @Router /api/order/{id}/refunds [post]
+func (h *Handler) CreateRefundHandler(ctx *fiber.Ctx) error {
+	var req RefundReq
+	if err := ctx.BodyParser(&req); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(
+			errorResponse("malformed refund body"))
+	}
+
+	request := dto.RefundRequest{
+		OrderUID: ctx.Params("id"),
+		ChrtIDs:  req.ChrtIDs,
+		Reason:   req.Reason,
+	}
+	if err := h.v.Validate(request); err != nil {
+		return ctx.Status(fiber.StatusUnprocessableEntity).JSON(
+			validationErrorResponse(err))
+	}
+
+	order, err := h.s.RefundOrder(ctx.UserContext(), request)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrOrderNotFound):
+			return ctx.Status(fiber.StatusNotFound).JSON(
+				errorResponse("order not found"))
+		case errors.Is(err, service.ErrNotRefundable):
+			return ctx.Status(fiber.StatusConflict).JSON(
+				errorResponse(reason(err, service.ErrNotRefundable)))
+		case errors.Is(err, service.ErrAlreadyRefunded):
+			return ctx.Status(fiber.StatusConflict).JSON(
+				errorResponse(reason(err, service.ErrAlreadyRefunded)))
+		case errors.Is(err, service.ErrStatusConflict):
+			return ctx.Status(fiber.StatusConflict).JSON(
+				errorResponse("order status changed concurrently, try again"))
+		case errors.Is(err, service.ErrItemNotFound):
+			return ctx.Status(fiber.StatusUnprocessableEntity).JSON(
+				errorResponse(reason(err, service.ErrItemNotFound)))
+		case errors.Is(err, service.ErrNothingToRefund):
+			return ctx.Status(fiber.StatusUnprocessableEntity).JSON(
+				errorResponse(reason(err, service.ErrNothingToRefund)))
+		case errors.Is(err, service.ErrRefundExceedsPayment):
+			return ctx.Status(fiber.StatusUnprocessableEntity).JSON(
+				errorResponse(reason(err, service.ErrRefundExceedsPayment)))
+		}
+		h.log.Error("failed to refund order", sl.Err(err))
+		return ctx.Status(fiber.StatusInternalServerError).JSON(
+			errorResponse("something went wrong, try again later"))
+	}
+
+	return ctx.Status(fiber.StatusCreated).JSON(order)
+}
@@ -0,0 +1,110 @@
+package rest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/ilam072/wbtech-l0/backend/internal/service"
+	"github.com/ilam072/wbtech-l0/backend/internal/types/dto"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func postRefund(t *testing.T, h *Handler, orderID string, body interface{}) *http.Response {
+	t.Helper()
+
+	payload, err := json.Marshal(body)
+	assert.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/order/"+orderID+"/refunds", bytes.NewReader(payload))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := h.api.Test(req)
+	assert.NoError(t, err)
+	return resp
+}
+
+func TestHandler_CreateRefundHandler_Created(t *testing.T) {
+	h, mockService := newStatusHandler(t)
+
+	orderID := uuid.New().String()
+	expected := dto.Order{OrderUID: orderID, Status: "delivered", RefundedAmount: 317, NetAmount: 1600}
+
+	mockService.EXPECT().RefundOrder(gomock.Any(), dto.RefundRequest{
+		OrderUID: orderID,
+		ChrtIDs:  []int{9934930},
+		Reason:   "damaged",
+	}).Return(expected, nil)
+
+	resp := postRefund(t, h, orderID, RefundReq{ChrtIDs: []int{9934930}, Reason: "damaged"})
+	assert.Equal(t, http.StatusCreated, resp.StatusCode)
+
+	var order dto.Order
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&order))
+	assert.Equal(t, expected, order)
+}
+
+func TestHandler_CreateRefundHandler_ValidationFailed(t *testing.T) {
+	h, mockService := newStatusHandler(t)
+
+	mockService.EXPECT().RefundOrder(gomock.Any(), gomock.Any()).Times(0)
+
+	resp := postRefund(t, h, uuid.New().String(), RefundReq{})
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.StatusCode)
+
+	var body ValidationErrorResp
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	assert.Len(t, body.Errors, 1)
+	assert.Equal(t, "reason", body.Errors[0].Field)
+}
+
+func TestHandler_CreateRefundHandler_Errors(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		statusCode int
+		message    string
+	}{
+		{"not found", service.ErrOrderNotFound, http.StatusNotFound, "order not found"},
+		{
+			"not refundable",
+			fmt.Errorf("op: %w: created", service.ErrNotRefundable),
+			http.StatusConflict,
+			"order cannot be refunded in its status: created",
+		},
+		{"already refunded", service.ErrAlreadyRefunded, http.StatusConflict, "order already refunded"},
+		{"concurrent change", service.ErrStatusConflict, http.StatusConflict, "order status changed concurrently, try again"},
+		{"unknown item", fmt.Errorf("op: %w: 42", service.ErrItemNotFound), http.StatusUnprocessableEntity, "order item not found: 42"},
+		{
+			"free item",
+			fmt.Errorf("op: %w: 42", service.ErrNothingToRefund),
+			http.StatusUnprocessableEntity,
+			"order item has nothing to refund: 42",
+		},
+		{
+			"exceeds payment",
+			fmt.Errorf("op: %w: 500 > 100", service.ErrRefundExceedsPayment),
+			http.StatusUnprocessableEntity,
+			"refunds exceed payment amount: 500 > 100",
+		},
+		{"internal error", fmt.Errorf("db is down"), http.StatusInternalServerError, "something went wrong, try again later"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, mockService := newStatusHandler(t)
+
+			mockService.EXPECT().RefundOrder(gomock.Any(), gomock.Any()).Return(dto.Order{}, tt.err)
+
+			resp := postRefund(t, h, uuid.New().String(), RefundReq{Reason: "damaged"})
+			assert.Equal(t, tt.statusCode, resp.StatusCode)
+
+			var body ErrorResp
+			assert.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+			assert.Equal(t, tt.message, body.Message)
+		})
+	}
+}
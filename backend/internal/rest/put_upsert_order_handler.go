@@ -0,0 +1,64 @@
+package rest
+
+import (
+	"errors"
+	"github.com/gofiber/fiber/v2"
+	"github.com/ilam072/wbtech-l0/backend/internal/service"
+	"github.com/ilam072/wbtech-l0/backend/internal/types/dto"
+	"github.com/ilam072/wbtech-l0/backend/pkg/logger/sl"
+	"log/slog"
+)
+
+// @Summary Create or update order
+// @Description Stores a new order or replaces the stored one if the version is higher. Delivery, payment and items
+// @Description are replaced, status and refunds are kept. Re-sending the stored content is a no-op.
+// @Tags order
+// @Accept json
+// @Produce json
+// @Param order body dto.Order true "order"
+// @Success 200 {object} UpsertResp "order updated or unchanged"
+// @Success 201 {object} UpsertResp "order created"
+// @Failure 400 {object} ErrorResp "malformed body"
+// @Failure 409 {object} ErrorResp "a newer version of the order is stored or the new one conflicts with its refunds"
+// @Failure 422 {object} ValidationErrorResp "validation failed"
+// @Failure 500 {object} ErrorResp "internal server error"
+// @Failure 503 {object} ErrorResp "temporary failure, retry the request"
+// @Router /api/orders [put]
+func (h *Handler) UpsertOrderHandler(ctx *fiber.Ctx) error {
+	var order dto.Order
+	if err := ctx.BodyParser(&order); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(
+			errorResponse("malformed order body"))
+	}
+
+	if err := h.v.Validate(order); err != nil {
+		return ctx.Status(fiber.StatusUnprocessableEntity).JSON(
+			validationErrorResponse(err))
+	}
+
+	outcome, err := h.s.UpsertOrder(ctx.UserContext(), order)
+	if errors.Is(err, service.ErrRefundsConflict) {
+		return ctx.Status(fiber.StatusConflict).JSON(
+			errorResponse("order revision conflicts with its refunds"))
+	}
+	if err != nil {
+		h.log.Error("failed to upsert order", slog.String("order_uid", order.OrderUID), sl.Err(err))
+		if errors.Is(err, service.ErrTransient) {
+			return ctx.Status(fiber.StatusServiceUnavailable).JSON(
+				errorResponse("temporary failure, try again later"))
+		}
+		return ctx.Status(fiber.StatusInternalServerError).JSON(
+			errorResponse("something went wrong, try again later"))
+	}
+
+	resp := UpsertResp{OrderUID: order.OrderUID, Outcome: string(outcome)}
+	switch outcome {
+	case service.UpsertInserted:
+		return ctx.Status(fiber.StatusCreated).JSON(resp)
+	case service.UpsertStale:
+		return ctx.Status(fiber.StatusConflict).JSON(
+			errorResponse("a newer version of the order is stored"))
+	default:
+		return ctx.Status(fiber.StatusOK).JSON(resp)
+	}
+}
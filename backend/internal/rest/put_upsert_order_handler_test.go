@@ -0,0 +1,78 @@
+package rest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/ilam072/wbtech-l0/backend/internal/service"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func putOrder(t *testing.T, h *Handler, body interface{}) *http.Response {
+	t.Helper()
+
+	payload, err := json.Marshal(body)
+	assert.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodPut, "/api/orders", bytes.NewReader(payload))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := h.api.Test(req)
+	assert.NoError(t, err)
+	return resp
+}
+
+func TestHandler_UpsertOrderHandler(t *testing.T) {
+	tests := []struct {
+		name       string
+		outcome    service.UpsertOutcome
+		err        error
+		statusCode int
+	}{
+		{"inserted", service.UpsertInserted, nil, http.StatusCreated},
+		{"updated", service.UpsertUpdated, nil, http.StatusOK},
+		{"unchanged", service.UpsertUnchanged, nil, http.StatusOK},
+		{"stale", service.UpsertStale, nil, http.StatusConflict},
+		{"refunds conflict", "", fmt.Errorf("upsert: %w", service.ErrRefundsConflict), http.StatusConflict},
+		{"transient error", "", fmt.Errorf("upsert: %w", service.ErrTransient), http.StatusServiceUnavailable},
+		{"internal error", "", fmt.Errorf("db is down"), http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, mockService := newStatusHandler(t)
+
+			order := validOrder()
+			order.Version = 2
+			mockService.EXPECT().UpsertOrder(gomock.Any(), order).Return(tt.outcome, tt.err)
+
+			resp := putOrder(t, h, order)
+			assert.Equal(t, tt.statusCode, resp.StatusCode)
+
+			if tt.statusCode == http.StatusOK || tt.statusCode == http.StatusCreated {
+				var body UpsertResp
+				assert.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+				assert.Equal(t, UpsertResp{OrderUID: order.OrderUID, Outcome: string(tt.outcome)}, body)
+			}
+		})
+	}
+}
+
+func TestHandler_UpsertOrderHandler_ValidationFailed(t *testing.T) {
+	h, mockService := newStatusHandler(t)
+
+	order := validOrder()
+	order.Version = -1
+	mockService.EXPECT().UpsertOrder(gomock.Any(), gomock.Any()).Times(0)
+
+	resp := putOrder(t, h, order)
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.StatusCode)
+
+	var body ValidationErrorResp
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	assert.Len(t, body.Errors, 1)
+	assert.Equal(t, "version", body.Errors[0].Field)
+}
@@ -1,5 +1,11 @@
 package rest
 
+import (
+	"errors"
+	"github.com/ilam072/wbtech-l0/backend/internal/validator"
+	"strings"
+)
+
 type ErrorResp struct {
 	Status  string
 	Message string
@@ -11,3 +17,58 @@ func errorResponse(message string) ErrorResp {
 		Message: message,
 	}
 }
+
+// reason strips the "op(): " prefixes added on the way up and keeps the
+// message starting from target, e.g. "invalid filter: malformed cursor".
+func reason(err, target error) string {
+	message := err.Error()
+	if i := strings.Index(message, target.Error()); i >= 0 {
+		return message[i:]
+	}
+	return target.Error()
+}
+
+type ValidationErrorResp struct {
+	Status  string
+	Message string
+	Errors  []validator.FieldError
+}
+
+// validationErrorResponse lists the failing fields when err is a
+// validator.ValidationError and falls back to the plain message otherwise.
+func validationErrorResponse(err error) ValidationErrorResp {
+	resp := ValidationErrorResp{
+		Status:  "error",
+		Message: "validation failed",
+	}
+
+	var verr *validator.ValidationError
+	if errors.As(err, &verr) {
+		resp.Errors = verr.Fields
+	} else {
+		resp.Errors = []validator.FieldError{{Message: err.Error()}}
+	}
+	return resp
+}
+
+type CreatedResp struct {
+	OrderUID string `json:"order_uid"`
+}
+
+type UpsertResp struct {
+	OrderUID string `json:"order_uid"`
+	Outcome  string `json:"outcome" example:"updated"`
+}
+
+type BatchItemResp struct {
+	OrderUID string                 `json:"order_uid"`
+	Status   int                    `json:"status"`
+	Message  string                 `json:"message,omitempty"`
+	Errors   []validator.FieldError `json:"errors,omitempty"`
+}
+
+type BatchResp struct {
+	Created int             `json:"created"`
+	Failed  int             `json:"failed"`
+	Results []BatchItemResp `json:"results"`
+}
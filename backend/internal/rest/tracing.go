@@ -0,0 +1,62 @@
+package rest
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/ilam072/wbtech-l0/backend/internal/tracing"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"net/http"
+)
+
+// headerCarrier adapts the request and response headers of a fiber context
+// to propagation.TextMapCarrier.
+type headerCarrier struct {
+	ctx *fiber.Ctx
+}
+
+func (c headerCarrier) Get(key string) string {
+	return c.ctx.Get(key)
+}
+
+func (c headerCarrier) Set(key, value string) {
+	c.ctx.Set(key, value)
+}
+
+func (c headerCarrier) Keys() []string {
+	keys := make([]string, 0)
+	c.ctx.Request().Header.VisitAll(func(key, _ []byte) {
+		keys = append(keys, string(key))
+	})
+	return keys
+}
+
+// startSpan starts a server span continuing the trace context sent by the
+// client, if any, and makes it the user context of the request, which
+// handlers pass on to the service.
+func startSpan(ctx *fiber.Ctx, name string) trace.Span {
+	parent := otel.GetTextMapPropagator().Extract(ctx.UserContext(), headerCarrier{ctx: ctx})
+
+	spanCtx, span := tracing.Tracer().Start(parent, name,
+		trace.WithSpanKind(trace.SpanKindServer),
+		trace.WithAttributes(
+			attribute.String("http.request.method", ctx.Method()),
+			attribute.String("http.route", ctx.Route().Path),
+			attribute.String("url.path", ctx.Path()),
+		),
+	)
+	ctx.SetUserContext(spanCtx)
+	return span
+}
+
+// endSpan records the response status on span and ends it.
+func endSpan(ctx *fiber.Ctx, span trace.Span) {
+	status := ctx.Response().StatusCode()
+
+	span.SetAttributes(attribute.Int("http.response.status_code", status))
+	if status >= fiber.StatusInternalServerError {
+		span.SetStatus(codes.Error, http.StatusText(status))
+	}
+	span.End()
+}
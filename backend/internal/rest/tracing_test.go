@@ -0,0 +1,83 @@
+package rest
+
+import (
+	"context"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/ilam072/wbtech-l0/backend/internal/service"
+	"github.com/ilam072/wbtech-l0/backend/internal/types/dto"
+	httpmock "github.com/ilam072/wbtech-l0/backend/mocks/http"
+	"github.com/ilam072/wbtech-l0/backend/pkg/logger/handlers/slogdiscard"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+	"go.uber.org/mock/gomock"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHandler_GetOrderHandler_Tracing(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	exporter := tracetest.NewInMemoryExporter()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter)))
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	defer otel.SetTracerProvider(noop.NewTracerProvider())
+
+	mockService := httpmock.NewMockOrderService(ctrl)
+	h := NewHandler(
+		slogdiscard.NewDiscardLogger(),
+		mockService,
+		httpmock.NewMockValidator(ctrl),
+		httpmock.NewMockCacheAdmin(ctrl),
+		httpmock.NewMockHealthChecker(ctrl),
+		"",
+	)
+
+	orderId := uuid.New().String()
+	mockService.EXPECT().GetOrder(gomock.Any(), orderId).DoAndReturn(func(ctx context.Context, _ string) (dto.Order, error) {
+		assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", trace.SpanContextFromContext(ctx).TraceID().String())
+		return dto.Order{}, service.ErrOrderNotFound
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/order/"+orderId, nil)
+	req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+
+	resp, err := h.api.Test(req)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 1)
+	assert.Equal(t, "GetOrderHandler", spans[0].Name)
+	assert.Equal(t, trace.SpanKindServer, spans[0].SpanKind)
+	assert.Equal(t, "00f067aa0ba902b7", spans[0].Parent.SpanID().String())
+	assert.Contains(t, spans[0].Attributes, attribute.Int("http.response.status_code", http.StatusNotFound))
+	assert.Contains(t, spans[0].Attributes, attribute.String("http.route", "/api/order/:id"))
+}
+
+func TestStartSpan_SetsUserContext(t *testing.T) {
+	otel.SetTracerProvider(sdktrace.NewTracerProvider())
+	defer otel.SetTracerProvider(noop.NewTracerProvider())
+
+	app := fiber.New()
+	app.Get("/", func(ctx *fiber.Ctx) error {
+		span := startSpan(ctx, "test")
+		defer endSpan(ctx, span)
+
+		assert.Equal(t, span.SpanContext(), trace.SpanContextFromContext(ctx.UserContext()))
+		return ctx.SendStatus(fiber.StatusNoContent)
+	})
+
+	resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/", nil))
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+}
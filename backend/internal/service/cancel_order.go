@@ -0,0 +1,87 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/ilam072/wbtech-l0/backend/internal/types/domain"
+	"github.com/ilam072/wbtech-l0/backend/internal/types/dto"
+	"github.com/ilam072/wbtech-l0/backend/pkg/e"
+	"time"
+)
+
+// CancelOrder cancels an order and returns it. Orders that were already paid
+// get the amount not refunded yet refunded in the same step. Cancelling a
+// cancelled order is a no-op.
+func (s OrderService) CancelOrder(ctx context.Context, orderId string, reason string) (_ dto.Order, err error) {
+	const op = "OrderService.CancelOrder()"
+
+	defer observe("CancelOrder", time.Now(), &err)
+
+	if _, err := uuid.Parse(orderId); err != nil {
+		return dto.Order{}, ErrInvalidUUID
+	}
+
+	fullOrder, err := s.orderRepo.GetOrder(ctx, orderId)
+	if err != nil {
+		return dto.Order{}, e.Wrap(op, mapRepoErr(err))
+	}
+
+	from := fullOrder.Order.Status
+	if from == domain.StatusCancelled {
+		return s.converter.DomainToDtoOrder(fullOrder), nil
+	}
+	if !CanTransition(from, domain.StatusCancelled) {
+		return dto.Order{}, e.Wrap(op, fmt.Errorf("%w: %s -> %s", ErrInvalidTransition, from, domain.StatusCancelled))
+	}
+
+	order, err := s.cancel(ctx, fullOrder, reason)
+	if err != nil {
+		return dto.Order{}, e.Wrap(op, err)
+	}
+	return order, nil
+}
+
+// cancel stores the cancellation of fullOrder, whose transition to cancelled
+// has been checked, together with the refund it implies.
+func (s OrderService) cancel(ctx context.Context, fullOrder domain.FullOrder, reason string) (dto.Order, error) {
+	now := time.Now().UTC()
+
+	var refunds []domain.Refund
+	if fullOrder.Order.Status != domain.StatusCreated {
+		if remaining := remainingAmount(fullOrder); remaining > 0 {
+			refunds = append(refunds, domain.Refund{
+				OrderID:   fullOrder.Order.ID,
+				Amount:    remaining,
+				Reason:    reason,
+				CreatedAt: now,
+			})
+		}
+	}
+
+	err := s.orderRepo.CancelOrder(ctx, domain.StatusChange{
+		OrderID:   fullOrder.Order.ID,
+		From:      fullOrder.Order.Status,
+		To:        domain.StatusCancelled,
+		Reason:    reason,
+		ChangedAt: now,
+	}, refunds)
+	if err != nil {
+		return dto.Order{}, mapRepoErr(err)
+	}
+
+	fullOrder.Order.Status = domain.StatusCancelled
+	fullOrder.Refunds = append(fullOrder.Refunds, refunds...)
+	s.cache.Invalidate(fullOrder.Order.ID.String())
+
+	return s.converter.DomainToDtoOrder(fullOrder), nil
+}
+
+// remainingAmount is the part of the payment not refunded yet.
+func remainingAmount(fullOrder domain.FullOrder) int {
+	remaining := fullOrder.Payment.Amount
+	for _, refund := range fullOrder.Refunds {
+		remaining -= refund.Amount
+	}
+	return remaining
+}
@@ -3,14 +3,27 @@ package service
 import (
 	"context"
 	"errors"
+	"fmt"
 	"github.com/ilam072/wbtech-l0/backend/internal/repo"
+	"github.com/ilam072/wbtech-l0/backend/internal/tracing"
+	"github.com/ilam072/wbtech-l0/backend/internal/types/domain"
 	"github.com/ilam072/wbtech-l0/backend/internal/types/dto"
 	"github.com/ilam072/wbtech-l0/backend/pkg/e"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"time"
 )
 
-func (s OrderService) CreateOrder(ctx context.Context, order dto.Order) error {
+func (s OrderService) CreateOrder(ctx context.Context, order dto.Order) (err error) {
 	const op = "OrderService.CreateOrder()"
 
+	defer observe("CreateOrder", time.Now(), &err)
+
+	ctx, span := tracing.Tracer().Start(ctx, op, trace.WithAttributes(attribute.String("order.uid", order.OrderUID)))
+	defer tracing.End(span, &err)
+
+	order = newOrder(order)
+
 	domainOrder, delivery, payment, items, err := s.converter.DtoToDomainOrder(order)
 	if err != nil {
 		return e.Wrap(op, err)
@@ -20,9 +33,22 @@ func (s OrderService) CreateOrder(ctx context.Context, order dto.Order) error {
 		if errors.Is(err, repo.ErrOrderExists) {
 			return e.Wrap(op, ErrOrderExists)
 		}
+		if errors.Is(err, repo.ErrTransient) {
+			return e.Wrap(op, fmt.Errorf("%w: %w", ErrTransient, err))
+		}
 		return e.Wrap(op, err)
 	}
 
 	s.cache.Set(order.OrderUID, order)
 	return nil
 }
+
+// newOrder returns order as it is stored on creation: in status created and
+// without refunds.
+func newOrder(order dto.Order) dto.Order {
+	order.Status = string(domain.StatusCreated)
+	order.Refunds = nil
+	order.RefundedAmount = 0
+	order.NetAmount = order.Payment.Amount
+	return order
+}
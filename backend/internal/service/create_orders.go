@@ -0,0 +1,77 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/ilam072/wbtech-l0/backend/internal/repo"
+	"github.com/ilam072/wbtech-l0/backend/internal/types/domain"
+	"github.com/ilam072/wbtech-l0/backend/internal/types/dto"
+	"github.com/ilam072/wbtech-l0/backend/pkg/e"
+)
+
+// CreateResult is the outcome of creating a single order of a batch. Err is
+// nil for stored orders and wraps ErrOrderExists for duplicates.
+type CreateResult struct {
+	OrderUID string
+	Err      error
+}
+
+// CreateOrders stores a batch of orders and reports the outcome of each one in
+// the same order as the input. The returned error is set only when the batch
+// as a whole could not be stored.
+func (s OrderService) CreateOrders(ctx context.Context, orders []dto.Order) ([]CreateResult, error) {
+	const op = "OrderService.CreateOrders()"
+
+	results := make([]CreateResult, len(orders))
+	fullOrders := make([]domain.FullOrder, 0, len(orders))
+	positions := make([]int, 0, len(orders))
+
+	for i, order := range orders {
+		results[i].OrderUID = order.OrderUID
+		order = newOrder(order)
+
+		domainOrder, delivery, payment, items, err := s.converter.DtoToDomainOrder(order)
+		if err != nil {
+			results[i].Err = e.Wrap(op, err)
+			continue
+		}
+
+		fullOrders = append(fullOrders, domain.FullOrder{
+			Order:    domainOrder,
+			Delivery: delivery,
+			Payment:  payment,
+			Items:    items,
+		})
+		positions = append(positions, i)
+	}
+
+	if len(fullOrders) == 0 {
+		return results, nil
+	}
+
+	created, err := s.orderRepo.CreateOrders(ctx, fullOrders)
+	if err != nil {
+		if errors.Is(err, repo.ErrTransient) {
+			return nil, e.Wrap(op, fmt.Errorf("%w: %w", ErrTransient, err))
+		}
+		return nil, e.Wrap(op, err)
+	}
+
+	for j, result := range created {
+		i := positions[j]
+
+		switch result.Outcome {
+		case repo.OutcomeInserted:
+			order := orders[i]
+			order = newOrder(order)
+			s.cache.Set(order.OrderUID, order)
+		case repo.OutcomeDuplicate:
+			results[i].Err = e.Wrap(op, ErrOrderExists)
+		default:
+			results[i].Err = e.Wrap(op, result.Err)
+		}
+	}
+
+	return results, nil
+}
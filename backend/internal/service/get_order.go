@@ -7,21 +7,19 @@ import (
 	"github.com/ilam072/wbtech-l0/backend/internal/repo"
 	"github.com/ilam072/wbtech-l0/backend/internal/types/dto"
 	"github.com/ilam072/wbtech-l0/backend/pkg/e"
+	"time"
 )
 
-func (s OrderService) GetOrder(ctx context.Context, orderId string) (dto.Order, error) {
+func (s OrderService) GetOrder(ctx context.Context, orderId string) (_ dto.Order, err error) {
 	const op = "OrderService.GetOrder()"
 
+	defer observe("GetOrder", time.Now(), &err)
+
 	if _, err := uuid.Parse(orderId); err != nil {
 		return dto.Order{}, ErrInvalidUUID
 	}
 
-	order, ok := s.cache.Get(orderId)
-	if ok {
-		return order, nil
-	}
-
-	fullOrder, err := s.orderRepo.GetOrder(ctx, orderId)
+	order, err := s.cache.Load(ctx, orderId)
 	if err != nil {
 		if errors.Is(err, repo.ErrOrderNotFound) {
 			return dto.Order{}, e.Wrap(op, ErrOrderNotFound)
@@ -29,5 +27,5 @@ func (s OrderService) GetOrder(ctx context.Context, orderId string) (dto.Order,
 		return dto.Order{}, e.Wrap(op, err)
 	}
 
-	return s.converter.DomainToDtoOrder(fullOrder), nil
+	return order, nil
 }
@@ -0,0 +1,45 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"github.com/ilam072/wbtech-l0/backend/internal/repo"
+	"github.com/ilam072/wbtech-l0/backend/internal/types/dto"
+	"github.com/ilam072/wbtech-l0/backend/pkg/e"
+)
+
+func (s OrderService) GetOrderByTrackNumber(ctx context.Context, trackNumber string) (dto.Order, error) {
+	const op = "OrderService.GetOrderByTrackNumber()"
+
+	order, ok := s.cache.GetByTrackNumber(trackNumber)
+	if ok {
+		return order, nil
+	}
+
+	fullOrder, err := s.orderRepo.GetOrderByTrackNumber(ctx, trackNumber)
+	if err != nil {
+		if errors.Is(err, repo.ErrOrderNotFound) {
+			return dto.Order{}, e.Wrap(op, ErrOrderNotFound)
+		}
+		return dto.Order{}, e.Wrap(op, err)
+	}
+
+	order = s.converter.DomainToDtoOrder(fullOrder)
+	s.cache.Set(order.OrderUID, order)
+
+	return order, nil
+}
+
+// GetCustomerOrders pages through the orders of a single customer, newest first.
+func (s OrderService) GetCustomerOrders(ctx context.Context, customerID string, filter dto.OrderFilter) (dto.OrderPage, error) {
+	const op = "OrderService.GetCustomerOrders()"
+
+	filter.CustomerID = customerID
+
+	page, err := s.ListOrders(ctx, filter)
+	if err != nil {
+		return dto.OrderPage{}, e.Wrap(op, err)
+	}
+
+	return page, nil
+}
@@ -0,0 +1,121 @@
+package service
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/ilam072/wbtech-l0/backend/internal/types/domain"
+	"github.com/ilam072/wbtech-l0/backend/internal/types/dto"
+	"github.com/ilam072/wbtech-l0/backend/pkg/e"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	defaultPageSize = 20
+	maxPageSize     = 100
+)
+
+func (s OrderService) ListOrders(ctx context.Context, filter dto.OrderFilter) (dto.OrderPage, error) {
+	const op = "OrderService.ListOrders()"
+
+	domainFilter, err := toDomainFilter(filter)
+	if err != nil {
+		return dto.OrderPage{}, e.Wrap(op, err)
+	}
+
+	limit := domainFilter.Limit
+	// fetch one extra order to find out whether there is a next page
+	domainFilter.Limit++
+
+	fullOrders, err := s.orderRepo.ListOrders(ctx, domainFilter)
+	if err != nil {
+		return dto.OrderPage{}, e.Wrap(op, err)
+	}
+
+	page := dto.OrderPage{Orders: make([]dto.Order, 0, min(len(fullOrders), limit))}
+
+	if len(fullOrders) > limit {
+		fullOrders = fullOrders[:limit]
+		last := fullOrders[limit-1].Order
+		page.NextCursor = encodeCursor(domain.OrderCursor{DateCreated: last.DateCreated, ID: last.ID})
+	}
+
+	for _, fullOrder := range fullOrders {
+		page.Orders = append(page.Orders, s.converter.DomainToDtoOrder(fullOrder))
+	}
+
+	return page, nil
+}
+
+func toDomainFilter(filter dto.OrderFilter) (domain.OrderFilter, error) {
+	f := domain.OrderFilter{
+		CustomerID:      filter.CustomerID,
+		TrackNumber:     filter.TrackNumber,
+		DeliveryService: filter.DeliveryService,
+		Currency:        filter.Currency,
+		Provider:        filter.Provider,
+		Brand:           filter.Brand,
+		Limit:           filter.Limit,
+	}
+
+	switch {
+	case f.Limit == 0:
+		f.Limit = defaultPageSize
+	case f.Limit < 0 || f.Limit > maxPageSize:
+		return domain.OrderFilter{}, fmt.Errorf("%w: limit must be between 1 and %d", ErrInvalidFilter, maxPageSize)
+	}
+
+	var err error
+	if filter.CreatedFrom != "" {
+		if f.CreatedFrom, err = time.Parse(time.RFC3339, filter.CreatedFrom); err != nil {
+			return domain.OrderFilter{}, fmt.Errorf("%w: created_from must be an RFC 3339 timestamp", ErrInvalidFilter)
+		}
+	}
+	if filter.CreatedTo != "" {
+		if f.CreatedTo, err = time.Parse(time.RFC3339, filter.CreatedTo); err != nil {
+			return domain.OrderFilter{}, fmt.Errorf("%w: created_to must be an RFC 3339 timestamp", ErrInvalidFilter)
+		}
+	}
+	if filter.Cursor != "" {
+		cursor, err := decodeCursor(filter.Cursor)
+		if err != nil {
+			return domain.OrderFilter{}, fmt.Errorf("%w: malformed cursor", ErrInvalidFilter)
+		}
+		f.After = &cursor
+	}
+
+	return f, nil
+}
+
+// encodeCursor packs the position of the last order of a page into an opaque token.
+func encodeCursor(c domain.OrderCursor) string {
+	raw := strconv.FormatInt(c.DateCreated.UnixMicro(), 10) + ":" + c.ID.String()
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func decodeCursor(token string) (domain.OrderCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return domain.OrderCursor{}, err
+	}
+
+	micros, id, ok := strings.Cut(string(raw), ":")
+	if !ok {
+		return domain.OrderCursor{}, fmt.Errorf("cursor %q has no separator", raw)
+	}
+
+	unixMicro, err := strconv.ParseInt(micros, 10, 64)
+	if err != nil {
+		return domain.OrderCursor{}, err
+	}
+
+	orderID, err := uuid.Parse(id)
+	if err != nil {
+		return domain.OrderCursor{}, err
+	}
+
+	return domain.OrderCursor{DateCreated: time.UnixMicro(unixMicro).UTC(), ID: orderID}, nil
+}
@@ -3,19 +3,32 @@ package service
 import (
 	"context"
 	"errors"
+	"github.com/ilam072/wbtech-l0/backend/internal/metrics"
+	"github.com/ilam072/wbtech-l0/backend/internal/repo"
 	"github.com/ilam072/wbtech-l0/backend/internal/types/domain"
 	"github.com/ilam072/wbtech-l0/backend/internal/types/dto"
+	"time"
 )
 
 //go:generate mockgen -source=order.go -destination=../../mocks/service/mock_order.go -package=mocks
 type OrderRepo interface {
 	CreateOrder(context.Context, domain.Order, domain.Delivery, domain.Payment, []domain.Item) error
+	CreateOrders(ctx context.Context, orders []domain.FullOrder) ([]repo.CreateResult, error)
+	UpsertOrder(ctx context.Context, order domain.FullOrder) (repo.CreateOutcome, error)
 	GetOrder(ctx context.Context, ID string) (domain.FullOrder, error)
+	GetOrderByTrackNumber(ctx context.Context, trackNumber string) (domain.FullOrder, error)
+	ListOrders(ctx context.Context, filter domain.OrderFilter) ([]domain.FullOrder, error)
+	UpdateOrderStatus(ctx context.Context, change domain.StatusChange) error
+	GetStatusHistory(ctx context.Context, orderID string) ([]domain.StatusChange, error)
+	CancelOrder(ctx context.Context, change domain.StatusChange, refunds []domain.Refund) error
+	CreateRefunds(ctx context.Context, status domain.OrderStatus, refunds []domain.Refund) error
 }
 
 type OrderCache interface {
 	Set(key string, order dto.Order)
-	Get(key string) (dto.Order, bool)
+	Load(ctx context.Context, key string) (dto.Order, error)
+	GetByTrackNumber(trackNumber string) (dto.Order, bool)
+	Invalidate(key string) bool
 }
 
 type OrderConverter interface {
@@ -27,6 +40,19 @@ var (
 	ErrOrderExists   = errors.New("order already exists")
 	ErrOrderNotFound = errors.New("order not found")
 	ErrInvalidUUID   = errors.New("invalid uuid")
+	ErrTransient     = errors.New("temporary failure")
+	ErrInvalidFilter = errors.New("invalid filter")
+
+	ErrInvalidStatus     = errors.New("unknown order status")
+	ErrInvalidTransition = errors.New("order status transition not allowed")
+	ErrStatusConflict    = errors.New("order status changed concurrently")
+
+	ErrNotRefundable        = errors.New("order cannot be refunded in its status")
+	ErrAlreadyRefunded      = errors.New("order already refunded")
+	ErrItemNotFound         = errors.New("order item not found")
+	ErrRefundExceedsPayment = errors.New("refunds exceed payment amount")
+	ErrNothingToRefund      = errors.New("order item has nothing to refund")
+	ErrRefundsConflict      = errors.New("order revision conflicts with its refunds")
 )
 
 type OrderService struct {
@@ -35,6 +61,14 @@ type OrderService struct {
 	converter OrderConverter
 }
 
+// observe records the duration of an operation, meant to be deferred with a
+// pointer to the named error result.
+func observe(operation string, start time.Time, err *error) {
+	metrics.ServiceDuration.
+		WithLabelValues(operation, metrics.Status(*err)).
+		Observe(time.Since(start).Seconds())
+}
+
 func NewOrderService(repo OrderRepo, cache OrderCache, converter OrderConverter) *OrderService {
 	return &OrderService{
 		orderRepo: repo,
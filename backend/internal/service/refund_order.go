@@ -0,0 +1,117 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/ilam072/wbtech-l0/backend/internal/types/domain"
+	"github.com/ilam072/wbtech-l0/backend/internal/types/dto"
+	"github.com/ilam072/wbtech-l0/backend/pkg/e"
+	"time"
+)
+
+// refundable lists the statuses in which money has been taken and may be
+// returned without cancelling the order.
+var refundable = map[domain.OrderStatus]bool{
+	domain.StatusPaid:      true,
+	domain.StatusAssembled: true,
+	domain.StatusShipped:   true,
+	domain.StatusDelivered: true,
+	domain.StatusReturned:  true,
+}
+
+// RefundOrder records a refund and returns the updated order. Each listed
+// item is refunded by its total price; without items the whole amount not
+// refunded yet is returned.
+func (s OrderService) RefundOrder(ctx context.Context, request dto.RefundRequest) (_ dto.Order, err error) {
+	const op = "OrderService.RefundOrder()"
+
+	defer observe("RefundOrder", time.Now(), &err)
+
+	if _, err := uuid.Parse(request.OrderUID); err != nil {
+		return dto.Order{}, ErrInvalidUUID
+	}
+
+	fullOrder, err := s.orderRepo.GetOrder(ctx, request.OrderUID)
+	if err != nil {
+		return dto.Order{}, e.Wrap(op, mapRepoErr(err))
+	}
+
+	status := fullOrder.Order.Status
+	if !refundable[status] {
+		return dto.Order{}, e.Wrap(op, fmt.Errorf("%w: %s", ErrNotRefundable, status))
+	}
+
+	refunds, err := buildRefunds(fullOrder, request, time.Now().UTC())
+	if err != nil {
+		return dto.Order{}, e.Wrap(op, err)
+	}
+
+	if err := s.orderRepo.CreateRefunds(ctx, status, refunds); err != nil {
+		return dto.Order{}, e.Wrap(op, mapRepoErr(err))
+	}
+
+	fullOrder.Refunds = append(fullOrder.Refunds, refunds...)
+	s.cache.Invalidate(request.OrderUID)
+
+	return s.converter.DomainToDtoOrder(fullOrder), nil
+}
+
+func buildRefunds(fullOrder domain.FullOrder, request dto.RefundRequest, now time.Time) ([]domain.Refund, error) {
+	remaining := remainingAmount(fullOrder)
+
+	if len(request.ChrtIDs) == 0 {
+		if remaining <= 0 {
+			return nil, ErrAlreadyRefunded
+		}
+		return []domain.Refund{{
+			OrderID:   fullOrder.Order.ID,
+			Amount:    remaining,
+			Reason:    request.Reason,
+			CreatedAt: now,
+		}}, nil
+	}
+
+	items := make(map[int64]domain.Item, len(fullOrder.Items))
+	for _, item := range fullOrder.Items {
+		items[item.ChrtID] = item
+	}
+	refunded := make(map[int64]bool, len(fullOrder.Refunds))
+	for _, refund := range fullOrder.Refunds {
+		if refund.ChrtID != nil {
+			refunded[*refund.ChrtID] = true
+		}
+	}
+
+	refunds := make([]domain.Refund, 0, len(request.ChrtIDs))
+	total := 0
+	for _, id := range request.ChrtIDs {
+		chrtID := int64(id)
+		item, ok := items[chrtID]
+		if !ok {
+			return nil, fmt.Errorf("%w: %d", ErrItemNotFound, id)
+		}
+		if refunded[chrtID] {
+			return nil, fmt.Errorf("%w: item %d", ErrAlreadyRefunded, id)
+		}
+		if item.TotalPrice <= 0 {
+			return nil, fmt.Errorf("%w: %d", ErrNothingToRefund, id)
+		}
+		refunded[chrtID] = true
+
+		refunds = append(refunds, domain.Refund{
+			OrderID:   fullOrder.Order.ID,
+			ChrtID:    &chrtID,
+			Amount:    item.TotalPrice,
+			Reason:    request.Reason,
+			CreatedAt: now,
+		})
+		total += item.TotalPrice
+	}
+
+	if total > remaining {
+		return nil, fmt.Errorf("%w: %d > %d", ErrRefundExceedsPayment, total, remaining)
+	}
+
+	return refunds, nil
+}
@@ -3,6 +3,7 @@ package service
 import (
 	"context"
 	"errors"
+	"fmt"
 	"github.com/google/uuid"
 	"github.com/ilam072/wbtech-l0/backend/internal/repo"
 	"github.com/ilam072/wbtech-l0/backend/internal/types/domain"
@@ -14,6 +15,13 @@ import (
 	"time"
 )
 
+// created returns order as stored by the service.
+func created(order dto.Order) dto.Order {
+	order.Status = string(domain.StatusCreated)
+	order.NetAmount = order.Payment.Amount
+	return order
+}
+
 func TestOrderService_CreateOrder(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -131,11 +139,11 @@ func TestOrderService_CreateOrder(t *testing.T) {
 		items = append(items, item)
 	}
 
-	converter.EXPECT().DtoToDomainOrder(dtoOrder).Return(domainOrder, delivery, payment, items, nil)
+	converter.EXPECT().DtoToDomainOrder(created(dtoOrder)).Return(domainOrder, delivery, payment, items, nil)
 
 	ctx := context.Background()
-	mockRepo.EXPECT().CreateOrder(ctx, domainOrder, delivery, payment, items).Return(nil)
-	cache.EXPECT().Set(dtoOrder.OrderUID, dtoOrder)
+	mockRepo.EXPECT().CreateOrder(gomock.Any(), domainOrder, delivery, payment, items).Return(nil)
+	cache.EXPECT().Set(dtoOrder.OrderUID, created(dtoOrder))
 
 	err := service.CreateOrder(ctx, dtoOrder)
 	assert.NoError(t, err)
@@ -154,14 +162,14 @@ func TestOrderService_GetOrderFromCache(t *testing.T) {
 	expectedOrder := dto.Order{
 		OrderUID: orderId,
 	}
-	cache.EXPECT().Get(orderId).Return(expectedOrder, true)
+	cache.EXPECT().Load(gomock.Any(), orderId).Return(expectedOrder, nil)
 
 	order, err := service.GetOrder(context.Background(), orderId)
 	assert.NoError(t, err)
 	assert.Equal(t, expectedOrder, order)
 }
 
-func TestOrderService_GetOrderFromRepo(t *testing.T) {
+func TestOrderService_GetOrder_InvalidUUID(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
@@ -170,25 +178,8 @@ func TestOrderService_GetOrderFromRepo(t *testing.T) {
 	converter := mocks.NewMockOrderConverter(ctrl)
 	service := NewOrderService(mockRepo, cache, converter)
 
-	orderId := uuid.New().String()
-
-	cache.EXPECT().Get(orderId).Return(dto.Order{}, false)
-
-	fullOrder := domain.FullOrder{
-		Order: domain.Order{
-			ID: uuid.MustParse(orderId),
-		},
-	}
-	mockRepo.EXPECT().GetOrder(gomock.Any(), orderId).Return(fullOrder, nil)
-
-	dtoOrder := dto.Order{
-		OrderUID: fullOrder.Order.ID.String(),
-	}
-	converter.EXPECT().DomainToDtoOrder(fullOrder).Return(dtoOrder)
-
-	order, err := service.GetOrder(context.Background(), orderId)
-	assert.NoError(t, err)
-	assert.Equal(t, dtoOrder, order)
+	_, err := service.GetOrder(context.Background(), "not-a-uuid")
+	assert.ErrorIs(t, err, ErrInvalidUUID)
 }
 
 func TestOrderService_GetOrder_NotFound(t *testing.T) {
@@ -202,8 +193,8 @@ func TestOrderService_GetOrder_NotFound(t *testing.T) {
 
 	orderId := uuid.New().String()
 
-	cache.EXPECT().Get(orderId).Return(dto.Order{}, false)
-	mockRepo.EXPECT().GetOrder(context.Background(), orderId).Return(domain.FullOrder{}, repo.ErrOrderNotFound)
+	cache.EXPECT().Load(context.Background(), orderId).
+		Return(dto.Order{}, fmt.Errorf("cache.Load(): %w", repo.ErrOrderNotFound))
 
 	dtoOrder, err := service.GetOrder(context.Background(), orderId)
 	assert.ErrorIs(t, err, ErrOrderNotFound)
@@ -222,8 +213,7 @@ func TestOrderService_GetOrder_RepoError(t *testing.T) {
 	orderId := uuid.New().String()
 	repoErr := errors.New("mockRepo error")
 
-	cache.EXPECT().Get(orderId).Return(dto.Order{}, false)
-	mockRepo.EXPECT().GetOrder(context.Background(), orderId).Return(domain.FullOrder{}, repoErr)
+	cache.EXPECT().Load(context.Background(), orderId).Return(dto.Order{}, repoErr)
 
 	dtoOrder, err := service.GetOrder(context.Background(), orderId)
 	assert.ErrorContains(t, err, repoErr.Error())
@@ -231,3 +221,763 @@ func TestOrderService_GetOrder_RepoError(t *testing.T) {
 
 	assert.Empty(t, dtoOrder)
 }
+
+func TestOrderService_CreateOrder_TransientError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockOrderRepo(ctrl)
+	cache := mocks.NewMockOrderCache(ctrl)
+	converter := mocks.NewMockOrderConverter(ctrl)
+	service := NewOrderService(mockRepo, cache, converter)
+
+	dtoOrder := dto.Order{OrderUID: uuid.New().String()}
+	domainOrder := domain.Order{ID: uuid.MustParse(dtoOrder.OrderUID)}
+	repoErr := fmt.Errorf("postgres.CreateOrder(): %w: %w", repo.ErrTransient, errors.New("connection refused"))
+
+	converter.EXPECT().DtoToDomainOrder(created(dtoOrder)).Return(domainOrder, domain.Delivery{}, domain.Payment{}, nil, nil)
+	mockRepo.EXPECT().CreateOrder(gomock.Any(), domainOrder, domain.Delivery{}, domain.Payment{}, nil).Return(repoErr)
+	cache.EXPECT().Set(gomock.Any(), gomock.Any()).Times(0)
+
+	err := service.CreateOrder(context.Background(), dtoOrder)
+	assert.ErrorIs(t, err, ErrTransient)
+	assert.ErrorContains(t, err, "connection refused")
+}
+
+func TestOrderService_CreateOrders(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockOrderRepo(ctrl)
+	cache := mocks.NewMockOrderCache(ctrl)
+	converter := mocks.NewMockOrderConverter(ctrl)
+	service := NewOrderService(mockRepo, cache, converter)
+
+	inserted := dto.Order{OrderUID: uuid.New().String()}
+	invalid := dto.Order{OrderUID: "not-a-uuid"}
+	duplicate := dto.Order{OrderUID: uuid.New().String()}
+	failed := dto.Order{OrderUID: uuid.New().String()}
+
+	fullOrder := func(order dto.Order) domain.FullOrder {
+		return domain.FullOrder{Order: domain.Order{ID: uuid.MustParse(order.OrderUID)}}
+	}
+	insertErr := errors.New("duplicate track number")
+
+	converter.EXPECT().DtoToDomainOrder(created(inserted)).Return(fullOrder(inserted).Order, domain.Delivery{}, domain.Payment{}, nil, nil)
+	converter.EXPECT().DtoToDomainOrder(created(invalid)).Return(domain.Order{}, domain.Delivery{}, domain.Payment{}, nil, errors.New("invalid UUID length"))
+	converter.EXPECT().DtoToDomainOrder(created(duplicate)).Return(fullOrder(duplicate).Order, domain.Delivery{}, domain.Payment{}, nil, nil)
+	converter.EXPECT().DtoToDomainOrder(created(failed)).Return(fullOrder(failed).Order, domain.Delivery{}, domain.Payment{}, nil, nil)
+
+	mockRepo.EXPECT().
+		CreateOrders(gomock.Any(), []domain.FullOrder{fullOrder(inserted), fullOrder(duplicate), fullOrder(failed)}).
+		Return([]repo.CreateResult{
+			{OrderID: fullOrder(inserted).Order.ID, Outcome: repo.OutcomeInserted},
+			{OrderID: fullOrder(duplicate).Order.ID, Outcome: repo.OutcomeDuplicate, Err: repo.ErrOrderExists},
+			{OrderID: fullOrder(failed).Order.ID, Outcome: repo.OutcomeFailed, Err: insertErr},
+		}, nil)
+	cache.EXPECT().Set(inserted.OrderUID, created(inserted))
+
+	results, err := service.CreateOrders(context.Background(), []dto.Order{inserted, invalid, duplicate, failed})
+	assert.NoError(t, err)
+	assert.Len(t, results, 4)
+
+	assert.Equal(t, inserted.OrderUID, results[0].OrderUID)
+	assert.NoError(t, results[0].Err)
+	assert.Equal(t, invalid.OrderUID, results[1].OrderUID)
+	assert.ErrorContains(t, results[1].Err, "invalid UUID length")
+	assert.ErrorIs(t, results[2].Err, ErrOrderExists)
+	assert.ErrorIs(t, results[3].Err, insertErr)
+}
+
+func TestOrderService_ListOrders_Pagination(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockOrderRepo(ctrl)
+	cache := mocks.NewMockOrderCache(ctrl)
+	converter := mocks.NewMockOrderConverter(ctrl)
+	service := NewOrderService(mockRepo, cache, converter)
+
+	createdAt := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	fullOrders := make([]domain.FullOrder, 3)
+	for i := range fullOrders {
+		fullOrders[i] = domain.FullOrder{Order: domain.Order{
+			ID:          uuid.New(),
+			CustomerID:  "test",
+			DateCreated: createdAt.Add(-time.Duration(i) * time.Hour),
+		}}
+	}
+
+	mockRepo.EXPECT().ListOrders(gomock.Any(), domain.OrderFilter{
+		CustomerID:  "test",
+		CreatedFrom: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+		Limit:       3,
+	}).Return(fullOrders, nil)
+	for _, fullOrder := range fullOrders[:2] {
+		converter.EXPECT().DomainToDtoOrder(fullOrder).Return(dto.Order{OrderUID: fullOrder.Order.ID.String()})
+	}
+
+	page, err := service.ListOrders(context.Background(), dto.OrderFilter{
+		CustomerID:  "test",
+		CreatedFrom: "2025-01-01T00:00:00Z",
+		Limit:       2,
+	})
+	assert.NoError(t, err)
+	assert.Len(t, page.Orders, 2)
+	assert.NotEmpty(t, page.NextCursor)
+
+	cursor, err := decodeCursor(page.NextCursor)
+	assert.NoError(t, err)
+	assert.Equal(t, fullOrders[1].Order.ID, cursor.ID)
+	assert.True(t, fullOrders[1].Order.DateCreated.Equal(cursor.DateCreated))
+
+	mockRepo.EXPECT().ListOrders(gomock.Any(), domain.OrderFilter{
+		CustomerID: "test",
+		After:      &cursor,
+		Limit:      3,
+	}).Return(fullOrders[2:], nil)
+	converter.EXPECT().DomainToDtoOrder(fullOrders[2]).Return(dto.Order{OrderUID: fullOrders[2].Order.ID.String()})
+
+	page, err = service.ListOrders(context.Background(), dto.OrderFilter{
+		CustomerID: "test",
+		Cursor:     page.NextCursor,
+		Limit:      2,
+	})
+	assert.NoError(t, err)
+	assert.Len(t, page.Orders, 1)
+	assert.Empty(t, page.NextCursor)
+}
+
+func TestOrderService_ListOrders_InvalidFilter(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockOrderRepo(ctrl)
+	cache := mocks.NewMockOrderCache(ctrl)
+	converter := mocks.NewMockOrderConverter(ctrl)
+	service := NewOrderService(mockRepo, cache, converter)
+
+	filters := []dto.OrderFilter{
+		{Limit: 101},
+		{Limit: -1},
+		{CreatedFrom: "yesterday"},
+		{CreatedTo: "2025-13-01"},
+		{Cursor: "not a cursor"},
+	}
+
+	for _, filter := range filters {
+		_, err := service.ListOrders(context.Background(), filter)
+		assert.ErrorIs(t, err, ErrInvalidFilter)
+	}
+}
+
+func TestOrderService_GetOrderByTrackNumber_FromCache(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockOrderRepo(ctrl)
+	cache := mocks.NewMockOrderCache(ctrl)
+	converter := mocks.NewMockOrderConverter(ctrl)
+	service := NewOrderService(mockRepo, cache, converter)
+
+	expectedOrder := dto.Order{OrderUID: uuid.New().String(), TrackNumber: "WBILMTESTTRACK"}
+	cache.EXPECT().GetByTrackNumber("WBILMTESTTRACK").Return(expectedOrder, true)
+
+	order, err := service.GetOrderByTrackNumber(context.Background(), "WBILMTESTTRACK")
+	assert.NoError(t, err)
+	assert.Equal(t, expectedOrder, order)
+}
+
+func TestOrderService_GetOrderByTrackNumber_FromRepo(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockOrderRepo(ctrl)
+	cache := mocks.NewMockOrderCache(ctrl)
+	converter := mocks.NewMockOrderConverter(ctrl)
+	service := NewOrderService(mockRepo, cache, converter)
+
+	fullOrder := domain.FullOrder{Order: domain.Order{ID: uuid.New(), TrackNumber: "WBILMTESTTRACK"}}
+	dtoOrder := dto.Order{OrderUID: fullOrder.Order.ID.String(), TrackNumber: "WBILMTESTTRACK"}
+
+	cache.EXPECT().GetByTrackNumber("WBILMTESTTRACK").Return(dto.Order{}, false)
+	mockRepo.EXPECT().GetOrderByTrackNumber(gomock.Any(), "WBILMTESTTRACK").Return(fullOrder, nil)
+	converter.EXPECT().DomainToDtoOrder(fullOrder).Return(dtoOrder)
+	cache.EXPECT().Set(dtoOrder.OrderUID, dtoOrder)
+
+	order, err := service.GetOrderByTrackNumber(context.Background(), "WBILMTESTTRACK")
+	assert.NoError(t, err)
+	assert.Equal(t, dtoOrder, order)
+}
+
+func TestOrderService_GetOrderByTrackNumber_NotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockOrderRepo(ctrl)
+	cache := mocks.NewMockOrderCache(ctrl)
+	converter := mocks.NewMockOrderConverter(ctrl)
+	service := NewOrderService(mockRepo, cache, converter)
+
+	cache.EXPECT().GetByTrackNumber("UNKNOWN").Return(dto.Order{}, false)
+	mockRepo.EXPECT().GetOrderByTrackNumber(gomock.Any(), "UNKNOWN").Return(domain.FullOrder{}, repo.ErrOrderNotFound)
+
+	_, err := service.GetOrderByTrackNumber(context.Background(), "UNKNOWN")
+	assert.ErrorIs(t, err, ErrOrderNotFound)
+}
+
+func TestCanTransition(t *testing.T) {
+	tests := []struct {
+		from, to domain.OrderStatus
+		allowed  bool
+	}{
+		{domain.StatusCreated, domain.StatusPaid, true},
+		{domain.StatusPaid, domain.StatusAssembled, true},
+		{domain.StatusAssembled, domain.StatusShipped, true},
+		{domain.StatusShipped, domain.StatusDelivered, true},
+		{domain.StatusDelivered, domain.StatusReturned, true},
+		{domain.StatusCreated, domain.StatusCancelled, true},
+		{domain.StatusAssembled, domain.StatusCancelled, true},
+		{domain.StatusShipped, domain.StatusReturned, true},
+		{domain.StatusCreated, domain.StatusShipped, false},
+		{domain.StatusShipped, domain.StatusCancelled, false},
+		{domain.StatusDelivered, domain.StatusPaid, false},
+		{domain.StatusCancelled, domain.StatusPaid, false},
+		{domain.StatusReturned, domain.StatusDelivered, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%s->%s", tt.from, tt.to), func(t *testing.T) {
+			assert.Equal(t, tt.allowed, CanTransition(tt.from, tt.to))
+		})
+	}
+}
+
+func TestOrderService_UpdateOrderStatus(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockOrderRepo(ctrl)
+	cache := mocks.NewMockOrderCache(ctrl)
+	converter := mocks.NewMockOrderConverter(ctrl)
+	service := NewOrderService(mockRepo, cache, converter)
+
+	stored := domain.FullOrder{Order: domain.Order{ID: uuid.New(), Status: domain.StatusCreated}}
+	paid := stored
+	paid.Order.Status = domain.StatusPaid
+	dtoOrder := dto.Order{OrderUID: stored.Order.ID.String(), Status: string(domain.StatusPaid)}
+
+	mockRepo.EXPECT().GetOrder(gomock.Any(), stored.Order.ID.String()).Return(stored, nil)
+	mockRepo.EXPECT().UpdateOrderStatus(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, change domain.StatusChange) error {
+			assert.Equal(t, stored.Order.ID, change.OrderID)
+			assert.Equal(t, domain.StatusCreated, change.From)
+			assert.Equal(t, domain.StatusPaid, change.To)
+			assert.Equal(t, "payment confirmed", change.Reason)
+			assert.False(t, change.ChangedAt.IsZero())
+			return nil
+		})
+	converter.EXPECT().DomainToDtoOrder(paid).Return(dtoOrder)
+	cache.EXPECT().Invalidate(stored.Order.ID.String()).Return(true)
+	cache.EXPECT().Set(gomock.Any(), gomock.Any()).Times(0)
+
+	order, err := service.UpdateOrderStatus(context.Background(), dto.StatusUpdate{
+		OrderUID: stored.Order.ID.String(),
+		Status:   "paid",
+		Reason:   "payment confirmed",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, dtoOrder, order)
+}
+
+func TestOrderService_UpdateOrderStatus_SameStatus(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockOrderRepo(ctrl)
+	cache := mocks.NewMockOrderCache(ctrl)
+	converter := mocks.NewMockOrderConverter(ctrl)
+	service := NewOrderService(mockRepo, cache, converter)
+
+	stored := domain.FullOrder{Order: domain.Order{ID: uuid.New(), Status: domain.StatusPaid}}
+	dtoOrder := dto.Order{OrderUID: stored.Order.ID.String(), Status: string(domain.StatusPaid)}
+
+	mockRepo.EXPECT().GetOrder(gomock.Any(), stored.Order.ID.String()).Return(stored, nil)
+	mockRepo.EXPECT().UpdateOrderStatus(gomock.Any(), gomock.Any()).Times(0)
+	converter.EXPECT().DomainToDtoOrder(stored).Return(dtoOrder)
+
+	order, err := service.UpdateOrderStatus(context.Background(), dto.StatusUpdate{
+		OrderUID: stored.Order.ID.String(),
+		Status:   "paid",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, dtoOrder, order)
+}
+
+func TestOrderService_UpdateOrderStatus_Errors(t *testing.T) {
+	orderID := uuid.New()
+
+	tests := []struct {
+		name      string
+		update    dto.StatusUpdate
+		current   domain.OrderStatus
+		getErr    error
+		updateErr error
+		expected  error
+	}{
+		{
+			name:     "invalid uuid",
+			update:   dto.StatusUpdate{OrderUID: "not-a-uuid", Status: "paid"},
+			expected: ErrInvalidUUID,
+		},
+		{
+			name:     "unknown status",
+			update:   dto.StatusUpdate{OrderUID: orderID.String(), Status: "lost"},
+			expected: ErrInvalidStatus,
+		},
+		{
+			name:     "not found",
+			update:   dto.StatusUpdate{OrderUID: orderID.String(), Status: "paid"},
+			getErr:   repo.ErrOrderNotFound,
+			expected: ErrOrderNotFound,
+		},
+		{
+			name:     "transition not allowed",
+			update:   dto.StatusUpdate{OrderUID: orderID.String(), Status: "paid"},
+			current:  domain.StatusDelivered,
+			expected: ErrInvalidTransition,
+		},
+		{
+			name:      "concurrent change",
+			update:    dto.StatusUpdate{OrderUID: orderID.String(), Status: "shipped"},
+			current:   domain.StatusAssembled,
+			updateErr: repo.ErrStatusConflict,
+			expected:  ErrStatusConflict,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mocks.NewMockOrderRepo(ctrl)
+			cache := mocks.NewMockOrderCache(ctrl)
+			converter := mocks.NewMockOrderConverter(ctrl)
+			service := NewOrderService(mockRepo, cache, converter)
+
+			stored := domain.FullOrder{Order: domain.Order{ID: orderID, Status: tt.current}}
+			if tt.current != "" || tt.getErr != nil {
+				mockRepo.EXPECT().GetOrder(gomock.Any(), orderID.String()).Return(stored, tt.getErr)
+			}
+			if tt.updateErr != nil {
+				mockRepo.EXPECT().UpdateOrderStatus(gomock.Any(), gomock.Any()).Return(tt.updateErr)
+			}
+			cache.EXPECT().Invalidate(gomock.Any()).Times(0)
+
+			_, err := service.UpdateOrderStatus(context.Background(), tt.update)
+			assert.ErrorIs(t, err, tt.expected)
+		})
+	}
+}
+
+func TestOrderService_GetOrderStatusHistory(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockOrderRepo(ctrl)
+	cache := mocks.NewMockOrderCache(ctrl)
+	converter := mocks.NewMockOrderConverter(ctrl)
+	service := NewOrderService(mockRepo, cache, converter)
+
+	orderID := uuid.New()
+	changedAt := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	cache.EXPECT().Load(gomock.Any(), orderID.String()).Return(dto.Order{OrderUID: orderID.String()}, nil)
+	mockRepo.EXPECT().GetStatusHistory(gomock.Any(), orderID.String()).Return([]domain.StatusChange{
+		{ID: 1, OrderID: orderID, From: domain.StatusCreated, To: domain.StatusPaid, ChangedAt: changedAt},
+	}, nil)
+
+	history, err := service.GetOrderStatusHistory(context.Background(), orderID.String())
+	assert.NoError(t, err)
+	assert.Equal(t, []dto.StatusChange{{From: "created", To: "paid", ChangedAt: changedAt}}, history)
+}
+
+func TestOrderService_GetOrderStatusHistory_NotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockOrderRepo(ctrl)
+	cache := mocks.NewMockOrderCache(ctrl)
+	converter := mocks.NewMockOrderConverter(ctrl)
+	service := NewOrderService(mockRepo, cache, converter)
+
+	orderID := uuid.New().String()
+
+	cache.EXPECT().Load(gomock.Any(), orderID).Return(dto.Order{}, repo.ErrOrderNotFound)
+	mockRepo.EXPECT().GetStatusHistory(gomock.Any(), gomock.Any()).Times(0)
+
+	_, err := service.GetOrderStatusHistory(context.Background(), orderID)
+	assert.ErrorIs(t, err, ErrOrderNotFound)
+}
+
+func refundableOrder(status domain.OrderStatus) domain.FullOrder {
+	id := uuid.New()
+	return domain.FullOrder{
+		Order:   domain.Order{ID: id, Status: status},
+		Payment: domain.Payment{OrderID: id, Amount: 1000},
+		Items: []domain.Item{
+			{ChrtID: 1, OrderID: id, TotalPrice: 300},
+			{ChrtID: 2, OrderID: id, TotalPrice: 500},
+			{ChrtID: 4, OrderID: id},
+		},
+	}
+}
+
+func TestOrderService_CancelOrder_Created(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockOrderRepo(ctrl)
+	cache := mocks.NewMockOrderCache(ctrl)
+	converter := mocks.NewMockOrderConverter(ctrl)
+	service := NewOrderService(mockRepo, cache, converter)
+
+	stored := refundableOrder(domain.StatusCreated)
+	orderID := stored.Order.ID.String()
+	dtoOrder := dto.Order{OrderUID: orderID, Status: string(domain.StatusCancelled)}
+
+	mockRepo.EXPECT().GetOrder(gomock.Any(), orderID).Return(stored, nil)
+	mockRepo.EXPECT().CancelOrder(gomock.Any(), gomock.Any(), gomock.Nil()).DoAndReturn(
+		func(_ context.Context, change domain.StatusChange, _ []domain.Refund) error {
+			assert.Equal(t, domain.StatusCreated, change.From)
+			assert.Equal(t, domain.StatusCancelled, change.To)
+			assert.Equal(t, "customer request", change.Reason)
+			return nil
+		})
+	cache.EXPECT().Invalidate(orderID).Return(true)
+	converter.EXPECT().DomainToDtoOrder(gomock.Any()).DoAndReturn(func(o domain.FullOrder) dto.Order {
+		assert.Equal(t, domain.StatusCancelled, o.Order.Status)
+		assert.Empty(t, o.Refunds)
+		return dtoOrder
+	})
+
+	order, err := service.CancelOrder(context.Background(), orderID, "customer request")
+	assert.NoError(t, err)
+	assert.Equal(t, dtoOrder, order)
+}
+
+func TestOrderService_CancelOrder_RefundsRemainder(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockOrderRepo(ctrl)
+	cache := mocks.NewMockOrderCache(ctrl)
+	converter := mocks.NewMockOrderConverter(ctrl)
+	service := NewOrderService(mockRepo, cache, converter)
+
+	stored := refundableOrder(domain.StatusAssembled)
+	chrtID := int64(1)
+	stored.Refunds = []domain.Refund{{OrderID: stored.Order.ID, ChrtID: &chrtID, Amount: 300}}
+	orderID := stored.Order.ID.String()
+
+	mockRepo.EXPECT().GetOrder(gomock.Any(), orderID).Return(stored, nil)
+	mockRepo.EXPECT().CancelOrder(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, _ domain.StatusChange, refunds []domain.Refund) error {
+			assert.Len(t, refunds, 1)
+			assert.Nil(t, refunds[0].ChrtID)
+			assert.Equal(t, 700, refunds[0].Amount)
+			assert.Equal(t, "out of stock", refunds[0].Reason)
+			return nil
+		})
+	cache.EXPECT().Invalidate(orderID).Return(true)
+	converter.EXPECT().DomainToDtoOrder(gomock.Any()).DoAndReturn(func(o domain.FullOrder) dto.Order {
+		assert.Len(t, o.Refunds, 2)
+		return dto.Order{OrderUID: orderID}
+	})
+
+	_, err := service.CancelOrder(context.Background(), orderID, "out of stock")
+	assert.NoError(t, err)
+}
+
+func TestOrderService_CancelOrder_Errors(t *testing.T) {
+	tests := []struct {
+		name      string
+		status    domain.OrderStatus
+		cancelErr error
+		expected  error
+	}{
+		{name: "shipped", status: domain.StatusShipped, expected: ErrInvalidTransition},
+		{name: "delivered", status: domain.StatusDelivered, expected: ErrInvalidTransition},
+		{name: "concurrent change", status: domain.StatusPaid, cancelErr: repo.ErrStatusConflict, expected: ErrStatusConflict},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mocks.NewMockOrderRepo(ctrl)
+			cache := mocks.NewMockOrderCache(ctrl)
+			converter := mocks.NewMockOrderConverter(ctrl)
+			service := NewOrderService(mockRepo, cache, converter)
+
+			stored := refundableOrder(tt.status)
+			mockRepo.EXPECT().GetOrder(gomock.Any(), stored.Order.ID.String()).Return(stored, nil)
+			if tt.cancelErr != nil {
+				mockRepo.EXPECT().CancelOrder(gomock.Any(), gomock.Any(), gomock.Any()).Return(tt.cancelErr)
+			}
+			cache.EXPECT().Invalidate(gomock.Any()).Times(0)
+
+			_, err := service.CancelOrder(context.Background(), stored.Order.ID.String(), "")
+			assert.ErrorIs(t, err, tt.expected)
+		})
+	}
+}
+
+func TestOrderService_UpdateOrderStatus_Cancelled(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockOrderRepo(ctrl)
+	cache := mocks.NewMockOrderCache(ctrl)
+	converter := mocks.NewMockOrderConverter(ctrl)
+	service := NewOrderService(mockRepo, cache, converter)
+
+	stored := refundableOrder(domain.StatusPaid)
+	orderID := stored.Order.ID.String()
+
+	mockRepo.EXPECT().GetOrder(gomock.Any(), orderID).Return(stored, nil)
+	mockRepo.EXPECT().UpdateOrderStatus(gomock.Any(), gomock.Any()).Times(0)
+	mockRepo.EXPECT().CancelOrder(gomock.Any(), gomock.Any(), gomock.Len(1)).Return(nil)
+	cache.EXPECT().Invalidate(orderID).Return(true)
+	converter.EXPECT().DomainToDtoOrder(gomock.Any()).Return(dto.Order{OrderUID: orderID})
+
+	_, err := service.UpdateOrderStatus(context.Background(), dto.StatusUpdate{OrderUID: orderID, Status: "cancelled"})
+	assert.NoError(t, err)
+}
+
+func TestOrderService_RefundOrder(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockOrderRepo(ctrl)
+	cache := mocks.NewMockOrderCache(ctrl)
+	converter := mocks.NewMockOrderConverter(ctrl)
+	service := NewOrderService(mockRepo, cache, converter)
+
+	stored := refundableOrder(domain.StatusDelivered)
+	orderID := stored.Order.ID.String()
+	dtoOrder := dto.Order{OrderUID: orderID, RefundedAmount: 500, NetAmount: 500}
+
+	mockRepo.EXPECT().GetOrder(gomock.Any(), orderID).Return(stored, nil)
+	mockRepo.EXPECT().CreateRefunds(gomock.Any(), domain.StatusDelivered, gomock.Any()).DoAndReturn(
+		func(_ context.Context, _ domain.OrderStatus, refunds []domain.Refund) error {
+			assert.Len(t, refunds, 1)
+			assert.Equal(t, int64(2), *refunds[0].ChrtID)
+			assert.Equal(t, 500, refunds[0].Amount)
+			assert.Equal(t, "damaged", refunds[0].Reason)
+			assert.False(t, refunds[0].CreatedAt.IsZero())
+			return nil
+		})
+	cache.EXPECT().Invalidate(orderID).Return(true)
+	converter.EXPECT().DomainToDtoOrder(gomock.Any()).Return(dtoOrder)
+
+	order, err := service.RefundOrder(context.Background(), dto.RefundRequest{
+		OrderUID: orderID,
+		ChrtIDs:  []int{2},
+		Reason:   "damaged",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, dtoOrder, order)
+}
+
+func TestOrderService_RefundOrder_Full(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockOrderRepo(ctrl)
+	cache := mocks.NewMockOrderCache(ctrl)
+	converter := mocks.NewMockOrderConverter(ctrl)
+	service := NewOrderService(mockRepo, cache, converter)
+
+	stored := refundableOrder(domain.StatusReturned)
+	chrtID := int64(1)
+	stored.Refunds = []domain.Refund{{OrderID: stored.Order.ID, ChrtID: &chrtID, Amount: 300}}
+	orderID := stored.Order.ID.String()
+
+	mockRepo.EXPECT().GetOrder(gomock.Any(), orderID).Return(stored, nil)
+	mockRepo.EXPECT().CreateRefunds(gomock.Any(), domain.StatusReturned, gomock.Any()).DoAndReturn(
+		func(_ context.Context, _ domain.OrderStatus, refunds []domain.Refund) error {
+			assert.Len(t, refunds, 1)
+			assert.Nil(t, refunds[0].ChrtID)
+			assert.Equal(t, 700, refunds[0].Amount)
+			return nil
+		})
+	cache.EXPECT().Invalidate(orderID).Return(true)
+	converter.EXPECT().DomainToDtoOrder(gomock.Any()).Return(dto.Order{OrderUID: orderID})
+
+	_, err := service.RefundOrder(context.Background(), dto.RefundRequest{OrderUID: orderID, Reason: "returned"})
+	assert.NoError(t, err)
+}
+
+func TestOrderService_RefundOrder_Errors(t *testing.T) {
+	chrtID := int64(1)
+
+	tests := []struct {
+		name      string
+		status    domain.OrderStatus
+		refunds   []domain.Refund
+		chrtIDs   []int
+		createErr error
+		expected  error
+	}{
+		{name: "not paid", status: domain.StatusCreated, expected: ErrNotRefundable},
+		{name: "cancelled", status: domain.StatusCancelled, expected: ErrNotRefundable},
+		{name: "unknown item", status: domain.StatusPaid, chrtIDs: []int{3}, expected: ErrItemNotFound},
+		{
+			name:     "item refunded before",
+			status:   domain.StatusPaid,
+			refunds:  []domain.Refund{{ChrtID: &chrtID, Amount: 300}},
+			chrtIDs:  []int{1},
+			expected: ErrAlreadyRefunded,
+		},
+		{name: "item listed twice", status: domain.StatusPaid, chrtIDs: []int{2, 2}, expected: ErrAlreadyRefunded},
+		{name: "free item", status: domain.StatusPaid, chrtIDs: []int{4}, expected: ErrNothingToRefund},
+		{
+			name:     "fully refunded",
+			status:   domain.StatusPaid,
+			refunds:  []domain.Refund{{Amount: 1000}},
+			expected: ErrAlreadyRefunded,
+		},
+		{
+			name:     "exceeds payment",
+			status:   domain.StatusPaid,
+			refunds:  []domain.Refund{{Amount: 800}},
+			chrtIDs:  []int{1},
+			expected: ErrRefundExceedsPayment,
+		},
+		{
+			name:      "concurrent change",
+			status:    domain.StatusShipped,
+			chrtIDs:   []int{1},
+			createErr: repo.ErrStatusConflict,
+			expected:  ErrStatusConflict,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mocks.NewMockOrderRepo(ctrl)
+			cache := mocks.NewMockOrderCache(ctrl)
+			converter := mocks.NewMockOrderConverter(ctrl)
+			service := NewOrderService(mockRepo, cache, converter)
+
+			stored := refundableOrder(tt.status)
+			stored.Refunds = tt.refunds
+			mockRepo.EXPECT().GetOrder(gomock.Any(), stored.Order.ID.String()).Return(stored, nil)
+			if tt.createErr != nil {
+				mockRepo.EXPECT().CreateRefunds(gomock.Any(), gomock.Any(), gomock.Any()).Return(tt.createErr)
+			}
+			cache.EXPECT().Invalidate(gomock.Any()).Times(0)
+
+			_, err := service.RefundOrder(context.Background(), dto.RefundRequest{
+				OrderUID: stored.Order.ID.String(),
+				ChrtIDs:  tt.chrtIDs,
+				Reason:   "test",
+			})
+			assert.ErrorIs(t, err, tt.expected)
+		})
+	}
+}
+
+func TestOrderService_UpsertOrder(t *testing.T) {
+	tests := []struct {
+		name     string
+		result   repo.CreateOutcome
+		expected UpsertOutcome
+		setCache bool
+		dropped  bool
+	}{
+		{name: "inserted", result: repo.OutcomeInserted, expected: UpsertInserted, setCache: true},
+		{name: "updated", result: repo.OutcomeUpdated, expected: UpsertUpdated, dropped: true},
+		{name: "unchanged", result: repo.OutcomeUnchanged, expected: UpsertUnchanged},
+		{name: "stale", result: repo.OutcomeStale, expected: UpsertStale},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mocks.NewMockOrderRepo(ctrl)
+			cache := mocks.NewMockOrderCache(ctrl)
+			converter := mocks.NewMockOrderConverter(ctrl)
+			service := NewOrderService(mockRepo, cache, converter)
+
+			dtoOrder := dto.Order{OrderUID: uuid.New().String(), Version: 3, Payment: dto.Payment{Amount: 1000}}
+			domainOrder := domain.Order{ID: uuid.MustParse(dtoOrder.OrderUID), Version: 3, ContentHash: "hash"}
+
+			converter.EXPECT().DtoToDomainOrder(created(dtoOrder)).Return(domainOrder, domain.Delivery{}, domain.Payment{}, nil, nil)
+			mockRepo.EXPECT().UpsertOrder(gomock.Any(), domain.FullOrder{Order: domainOrder}).Return(tt.result, nil)
+			if tt.setCache {
+				cache.EXPECT().Set(dtoOrder.OrderUID, created(dtoOrder))
+			}
+			if tt.dropped {
+				cache.EXPECT().Invalidate(dtoOrder.OrderUID).Return(true)
+			}
+
+			outcome, err := service.UpsertOrder(context.Background(), dtoOrder)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, outcome)
+		})
+	}
+}
+
+func TestOrderService_UpsertOrder_RetriesConcurrentInsert(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockOrderRepo(ctrl)
+	cache := mocks.NewMockOrderCache(ctrl)
+	converter := mocks.NewMockOrderConverter(ctrl)
+	service := NewOrderService(mockRepo, cache, converter)
+
+	dtoOrder := dto.Order{OrderUID: uuid.New().String(), Version: 2}
+	domainOrder := domain.Order{ID: uuid.MustParse(dtoOrder.OrderUID), Version: 2}
+	repoErr := fmt.Errorf("postgres.UpsertOrder(): %w: %w", repo.ErrConcurrentInsert, errors.New("duplicate key"))
+
+	converter.EXPECT().DtoToDomainOrder(created(dtoOrder)).Return(domainOrder, domain.Delivery{}, domain.Payment{}, nil, nil)
+	gomock.InOrder(
+		mockRepo.EXPECT().UpsertOrder(gomock.Any(), domain.FullOrder{Order: domainOrder}).Return(repo.CreateOutcome(""), repoErr),
+		mockRepo.EXPECT().UpsertOrder(gomock.Any(), domain.FullOrder{Order: domainOrder}).Return(repo.OutcomeUpdated, nil),
+	)
+	cache.EXPECT().Invalidate(dtoOrder.OrderUID).Return(true)
+
+	outcome, err := service.UpsertOrder(context.Background(), dtoOrder)
+	assert.NoError(t, err)
+	assert.Equal(t, UpsertUpdated, outcome)
+}
+
+func TestOrderService_UpsertOrder_TransientError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockOrderRepo(ctrl)
+	cache := mocks.NewMockOrderCache(ctrl)
+	converter := mocks.NewMockOrderConverter(ctrl)
+	service := NewOrderService(mockRepo, cache, converter)
+
+	dtoOrder := dto.Order{OrderUID: uuid.New().String()}
+	domainOrder := domain.Order{ID: uuid.MustParse(dtoOrder.OrderUID)}
+	repoErr := fmt.Errorf("postgres.UpsertOrder(): %w: %w", repo.ErrTransient, errors.New("deadlock detected"))
+
+	converter.EXPECT().DtoToDomainOrder(created(dtoOrder)).Return(domainOrder, domain.Delivery{}, domain.Payment{}, nil, nil)
+	mockRepo.EXPECT().UpsertOrder(gomock.Any(), gomock.Any()).Return(repo.CreateOutcome(""), repoErr)
+
+	_, err := service.UpsertOrder(context.Background(), dtoOrder)
+	assert.ErrorIs(t, err, ErrTransient)
+}
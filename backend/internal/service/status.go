@@ -0,0 +1,34 @@
+package service
+
+import (
+	"github.com/ilam072/wbtech-l0/backend/internal/types/domain"
+)
+
+// transitions lists the statuses each status may move to. Cancelled and
+// returned are final.
+var transitions = map[domain.OrderStatus][]domain.OrderStatus{
+	domain.StatusCreated:   {domain.StatusPaid, domain.StatusCancelled},
+	domain.StatusPaid:      {domain.StatusAssembled, domain.StatusCancelled},
+	domain.StatusAssembled: {domain.StatusShipped, domain.StatusCancelled},
+	domain.StatusShipped:   {domain.StatusDelivered, domain.StatusReturned},
+	domain.StatusDelivered: {domain.StatusReturned},
+	domain.StatusCancelled: nil,
+	domain.StatusReturned:  nil,
+}
+
+// ParseStatus returns the lifecycle status named s.
+func ParseStatus(s string) (domain.OrderStatus, bool) {
+	status := domain.OrderStatus(s)
+	_, ok := transitions[status]
+	return status, ok
+}
+
+// CanTransition reports whether an order may move from one status to another.
+func CanTransition(from, to domain.OrderStatus) bool {
+	for _, next := range transitions[from] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
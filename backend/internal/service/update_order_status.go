@@ -0,0 +1,122 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/ilam072/wbtech-l0/backend/internal/repo"
+	"github.com/ilam072/wbtech-l0/backend/internal/types/domain"
+	"github.com/ilam072/wbtech-l0/backend/internal/types/dto"
+	"github.com/ilam072/wbtech-l0/backend/pkg/e"
+	"time"
+)
+
+// UpdateOrderStatus moves an order to update.Status if the lifecycle allows
+// it and returns the updated order. Repeating the current status is a no-op,
+// so redelivered status events are harmless. Cancellation goes through the
+// same path as CancelOrder.
+func (s OrderService) UpdateOrderStatus(ctx context.Context, update dto.StatusUpdate) (_ dto.Order, err error) {
+	const op = "OrderService.UpdateOrderStatus()"
+
+	defer observe("UpdateOrderStatus", time.Now(), &err)
+
+	orderID, err := uuid.Parse(update.OrderUID)
+	if err != nil {
+		return dto.Order{}, ErrInvalidUUID
+	}
+
+	to, ok := ParseStatus(update.Status)
+	if !ok {
+		return dto.Order{}, e.Wrap(op, fmt.Errorf("%w: %q", ErrInvalidStatus, update.Status))
+	}
+
+	fullOrder, err := s.orderRepo.GetOrder(ctx, update.OrderUID)
+	if err != nil {
+		return dto.Order{}, e.Wrap(op, mapRepoErr(err))
+	}
+
+	from := fullOrder.Order.Status
+	if from == to {
+		return s.converter.DomainToDtoOrder(fullOrder), nil
+	}
+	if !CanTransition(from, to) {
+		return dto.Order{}, e.Wrap(op, fmt.Errorf("%w: %s -> %s", ErrInvalidTransition, from, to))
+	}
+	if to == domain.StatusCancelled {
+		order, err := s.cancel(ctx, fullOrder, update.Reason)
+		if err != nil {
+			return dto.Order{}, e.Wrap(op, err)
+		}
+		return order, nil
+	}
+
+	err = s.orderRepo.UpdateOrderStatus(ctx, domain.StatusChange{
+		OrderID:   orderID,
+		From:      from,
+		To:        to,
+		Reason:    update.Reason,
+		ChangedAt: time.Now().UTC(),
+	})
+	if err != nil {
+		return dto.Order{}, e.Wrap(op, mapRepoErr(err))
+	}
+
+	// fullOrder was read before the update, so caching it could overwrite a
+	// newer status written concurrently.
+	s.cache.Invalidate(update.OrderUID)
+
+	fullOrder.Order.Status = to
+	return s.converter.DomainToDtoOrder(fullOrder), nil
+}
+
+// GetOrderStatusHistory returns the status changes of an existing order,
+// oldest first.
+func (s OrderService) GetOrderStatusHistory(ctx context.Context, orderId string) ([]dto.StatusChange, error) {
+	const op = "OrderService.GetOrderStatusHistory()"
+
+	if _, err := uuid.Parse(orderId); err != nil {
+		return nil, ErrInvalidUUID
+	}
+
+	if _, err := s.cache.Load(ctx, orderId); err != nil {
+		return nil, e.Wrap(op, mapRepoErr(err))
+	}
+
+	history, err := s.orderRepo.GetStatusHistory(ctx, orderId)
+	if err != nil {
+		return nil, e.Wrap(op, mapRepoErr(err))
+	}
+
+	changes := make([]dto.StatusChange, 0, len(history))
+	for _, change := range history {
+		changes = append(changes, dto.StatusChange{
+			From:      string(change.From),
+			To:        string(change.To),
+			Reason:    change.Reason,
+			ChangedAt: change.ChangedAt,
+		})
+	}
+
+	return changes, nil
+}
+
+// mapRepoErr translates repository errors into service errors.
+func mapRepoErr(err error) error {
+	switch {
+	case errors.Is(err, repo.ErrOrderNotFound):
+		return ErrOrderNotFound
+	case errors.Is(err, repo.ErrStatusConflict):
+		return ErrStatusConflict
+	case errors.Is(err, repo.ErrAlreadyRefunded):
+		return ErrAlreadyRefunded
+	case errors.Is(err, repo.ErrRefundExceedsPayment):
+		return ErrRefundExceedsPayment
+	case errors.Is(err, repo.ErrRefundsConflict):
+		return ErrRefundsConflict
+	case errors.Is(err, repo.ErrTransient):
+		return fmt.Errorf("%w: %w", ErrTransient, err)
+	default:
+		return err
+	}
+}
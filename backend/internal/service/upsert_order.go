@@ -0,0 +1,88 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/ilam072/wbtech-l0/backend/internal/repo"
+	"github.com/ilam072/wbtech-l0/backend/internal/tracing"
+	"github.com/ilam072/wbtech-l0/backend/internal/types/domain"
+	"github.com/ilam072/wbtech-l0/backend/internal/types/dto"
+	"github.com/ilam072/wbtech-l0/backend/pkg/e"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"time"
+)
+
+// UpsertOutcome tells what UpsertOrder did with an order.
+type UpsertOutcome string
+
+const (
+	// UpsertInserted means the order was new and has been stored.
+	UpsertInserted UpsertOutcome = "inserted"
+	// UpsertUpdated means a higher version replaced the stored order.
+	UpsertUpdated UpsertOutcome = "updated"
+	// UpsertUnchanged means the same content is already stored.
+	UpsertUnchanged UpsertOutcome = "unchanged"
+	// UpsertStale means a different revision with the same or a higher
+	// version is stored, so the order was ignored.
+	UpsertStale UpsertOutcome = "stale"
+)
+
+// UpsertOrder stores a new order or replaces the stored one with a newer
+// version of it. Replaying an order is safe: identical and older revisions
+// leave the stored order as is.
+func (s OrderService) UpsertOrder(ctx context.Context, order dto.Order) (_ UpsertOutcome, err error) {
+	const op = "OrderService.UpsertOrder()"
+
+	defer observe("UpsertOrder", time.Now(), &err)
+
+	ctx, span := tracing.Tracer().Start(ctx, op, trace.WithAttributes(
+		attribute.String("order.uid", order.OrderUID),
+		attribute.Int64("order.version", order.Version),
+	))
+	defer tracing.End(span, &err)
+
+	order = newOrder(order)
+
+	domainOrder, delivery, payment, items, err := s.converter.DtoToDomainOrder(order)
+	if err != nil {
+		return "", e.Wrap(op, err)
+	}
+
+	fullOrder := domain.FullOrder{
+		Order:    domainOrder,
+		Delivery: delivery,
+		Payment:  payment,
+		Items:    items,
+	}
+	result, err := s.orderRepo.UpsertOrder(ctx, fullOrder)
+	if errors.Is(err, repo.ErrConcurrentInsert) {
+		// Another request inserted the order first. The second attempt reads
+		// it under lock and compares the versions.
+		result, err = s.orderRepo.UpsertOrder(ctx, fullOrder)
+	}
+	if err != nil {
+		return "", e.Wrap(op, mapRepoErr(err))
+	}
+
+	var outcome UpsertOutcome
+	switch result {
+	case repo.OutcomeInserted:
+		outcome = UpsertInserted
+		s.cache.Set(order.OrderUID, order)
+	case repo.OutcomeUpdated:
+		// the stored status and refunds are kept, the next read loads them
+		outcome = UpsertUpdated
+		s.cache.Invalidate(order.OrderUID)
+	case repo.OutcomeUnchanged:
+		outcome = UpsertUnchanged
+	case repo.OutcomeStale:
+		outcome = UpsertStale
+	default:
+		return "", e.Wrap(op, fmt.Errorf("unexpected upsert outcome %q", result))
+	}
+	span.SetAttributes(attribute.String("order.upsert.outcome", string(outcome)))
+
+	return outcome, nil
+}
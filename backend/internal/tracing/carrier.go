@@ -0,0 +1,56 @@
+package tracing
+
+import (
+	"context"
+	"github.com/segmentio/kafka-go"
+	"go.opentelemetry.io/otel"
+	"strings"
+)
+
+// MessageCarrier adapts Kafka message headers to propagation.TextMapCarrier.
+// Header keys are matched case-insensitively.
+type MessageCarrier struct {
+	msg *kafka.Message
+}
+
+func NewMessageCarrier(msg *kafka.Message) MessageCarrier {
+	return MessageCarrier{msg: msg}
+}
+
+func (c MessageCarrier) Get(key string) string {
+	for _, h := range c.msg.Headers {
+		if strings.EqualFold(h.Key, key) {
+			return string(h.Value)
+		}
+	}
+	return ""
+}
+
+func (c MessageCarrier) Set(key, value string) {
+	for i, h := range c.msg.Headers {
+		if strings.EqualFold(h.Key, key) {
+			c.msg.Headers[i].Value = []byte(value)
+			return
+		}
+	}
+	c.msg.Headers = append(c.msg.Headers, kafka.Header{Key: key, Value: []byte(value)})
+}
+
+func (c MessageCarrier) Keys() []string {
+	keys := make([]string, 0, len(c.msg.Headers))
+	for _, h := range c.msg.Headers {
+		keys = append(keys, h.Key)
+	}
+	return keys
+}
+
+// ExtractMessage returns ctx carrying the remote span context found in the
+// headers of msg, if any.
+func ExtractMessage(ctx context.Context, msg kafka.Message) context.Context {
+	return otel.GetTextMapPropagator().Extract(ctx, NewMessageCarrier(&msg))
+}
+
+// InjectMessage writes the span context of ctx to the headers of msg.
+func InjectMessage(ctx context.Context, msg *kafka.Message) {
+	otel.GetTextMapPropagator().Inject(ctx, NewMessageCarrier(msg))
+}
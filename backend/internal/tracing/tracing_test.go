@@ -0,0 +1,68 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"github.com/segmentio/kafka-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"testing"
+)
+
+func TestMessageCarrier_RoundTrip(t *testing.T) {
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+
+	ctx, span := provider.Tracer("test").Start(context.Background(), "produce")
+	span.End()
+
+	msg := kafka.Message{Headers: []kafka.Header{{Key: "other", Value: []byte("value")}}}
+	InjectMessage(ctx, &msg)
+	InjectMessage(ctx, &msg)
+
+	assert.Len(t, msg.Headers, 2)
+	assert.Equal(t, "value", NewMessageCarrier(&msg).Get("other"))
+
+	remote := trace.SpanContextFromContext(ExtractMessage(context.Background(), msg))
+	assert.True(t, remote.IsRemote())
+	assert.Equal(t, span.SpanContext().TraceID(), remote.TraceID())
+	assert.Equal(t, span.SpanContext().SpanID(), remote.SpanID())
+}
+
+func TestMessageCarrier_CaseInsensitive(t *testing.T) {
+	msg := kafka.Message{Headers: []kafka.Header{{Key: "Traceparent", Value: []byte("00-1")}}}
+
+	assert.Equal(t, "00-1", NewMessageCarrier(&msg).Get("traceparent"))
+}
+
+func TestEnd(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	tracer := provider.Tracer("test")
+
+	func() (err error) {
+		_, span := tracer.Start(context.Background(), "failed")
+		defer End(span, &err)
+		return errors.New("boom")
+	}()
+	func() (err error) {
+		_, span := tracer.Start(context.Background(), "ok")
+		defer End(span, &err)
+		return nil
+	}()
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 2)
+	assert.Equal(t, codes.Error, spans[0].Status.Code)
+	assert.Equal(t, "boom", spans[0].Status.Description)
+	assert.Len(t, spans[0].Events, 1)
+	assert.Equal(t, codes.Unset, spans[1].Status.Code)
+}
@@ -10,20 +10,50 @@ type FullOrder struct {
 	Delivery Delivery
 	Payment  Payment
 	Items    []Item
+	Refunds  []Refund
 }
 
 type Order struct {
-	ID                uuid.UUID `db:"id"`
-	TrackNumber       string    `db:"track_number"`
-	Entry             string    `db:"entry"`
-	Locale            string    `db:"locale"`
-	InternalSignature string    `db:"internal_signature"`
-	CustomerID        string    `db:"customer_id"`
-	DeliveryService   string    `db:"delivery_service"`
-	ShardKey          string    `db:"shardkey"`
-	SmID              int       `db:"sm_id"`
-	DateCreated       time.Time `db:"date_created"`
-	OofShard          string    `db:"oof_shard"`
+	ID                uuid.UUID   `db:"id"`
+	TrackNumber       string      `db:"track_number"`
+	Entry             string      `db:"entry"`
+	Locale            string      `db:"locale"`
+	InternalSignature string      `db:"internal_signature"`
+	CustomerID        string      `db:"customer_id"`
+	DeliveryService   string      `db:"delivery_service"`
+	ShardKey          string      `db:"shardkey"`
+	SmID              int         `db:"sm_id"`
+	DateCreated       time.Time   `db:"date_created"`
+	OofShard          string      `db:"oof_shard"`
+	Status            OrderStatus `db:"status"`
+	// Version and ContentHash tell revisions of the same order apart, see
+	// OrderRepo.UpsertOrder. UpdatedAt is set by the database on every change.
+	Version     int64     `db:"version"`
+	ContentHash string    `db:"content_hash"`
+	UpdatedAt   time.Time `db:"updated_at" goqu:"defaultifempty"`
+}
+
+// OrderStatus is a stage of the order lifecycle.
+type OrderStatus string
+
+const (
+	StatusCreated   OrderStatus = "created"
+	StatusPaid      OrderStatus = "paid"
+	StatusAssembled OrderStatus = "assembled"
+	StatusShipped   OrderStatus = "shipped"
+	StatusDelivered OrderStatus = "delivered"
+	StatusCancelled OrderStatus = "cancelled"
+	StatusReturned  OrderStatus = "returned"
+)
+
+// StatusChange is a row of the order status history.
+type StatusChange struct {
+	ID        int64       `db:"-"`
+	OrderID   uuid.UUID   `db:"order_id"`
+	From      OrderStatus `db:"from_status"`
+	To        OrderStatus `db:"to_status"`
+	Reason    string      `db:"reason"`
+	ChangedAt time.Time   `db:"changed_at"`
 }
 
 type Delivery struct {
@@ -66,3 +96,58 @@ type Item struct {
 	Brand       string    `db:"brand"`
 	Status      int       `db:"status"`
 }
+
+// Refund is money returned for the whole order, when ChrtID is nil, or for
+// one of its items.
+type Refund struct {
+	ID        int64     `db:"-"`
+	OrderID   uuid.UUID `db:"order_id"`
+	ChrtID    *int64    `db:"chrt_id"`
+	Amount    int       `db:"amount"`
+	Reason    string    `db:"reason"`
+	CreatedAt time.Time `db:"created_at"`
+}
+
+type OrderFilter struct {
+	CustomerID      string
+	TrackNumber     string
+	DeliveryService string
+	CreatedFrom     time.Time
+	CreatedTo       time.Time
+	Currency        string
+	Provider        string
+	Brand           string
+	After           *OrderCursor
+	Limit           int
+}
+
+// OrderCursor points at the last order of a page ordered by date_created, id descending.
+type OrderCursor struct {
+	DateCreated time.Time
+	ID          uuid.UUID
+}
+
+const (
+	// EventOrderCreated is published once an order has been stored.
+	EventOrderCreated = "order.created"
+	// EventOrderUpdated is published once a newer version replaced an order.
+	EventOrderUpdated = "order.updated"
+)
+
+// OutboxEvent is an event stored in the outbox in the transaction that caused
+// it and published to Kafka afterwards.
+type OutboxEvent struct {
+	ID          int64     `db:"-"`
+	AggregateID uuid.UUID `db:"aggregate_id"`
+	Type        string    `db:"event_type"`
+	Payload     []byte    `db:"payload"`
+	Attempts    int       `db:"-"`
+	CreatedAt   time.Time `db:"created_at" goqu:"defaultifempty"`
+}
+
+// Watermark summarizes the state of the orders table. Two equal watermarks
+// mean no order was added, removed or changed in between.
+type Watermark struct {
+	OrderCount  int64
+	LastUpdated time.Time
+}
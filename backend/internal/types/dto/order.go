@@ -19,6 +19,16 @@ type Order struct {
 	SmID              int       `json:"sm_id" validate:"required"`
 	DateCreated       time.Time `json:"date_created"`
 	OofShard          string    `json:"oof_shard" validate:"required"`
+	// Version orders revisions of the same order: a higher version replaces
+	// the stored one on upsert, a lower one is ignored.
+	Version int64 `json:"version" validate:"min=0"`
+	// Status is assigned by the service: new orders always start as created.
+	Status string `json:"status"`
+	// Refunds, RefundedAmount and NetAmount are maintained by the service.
+	// NetAmount is payment.amount less RefundedAmount.
+	Refunds        []Refund `json:"refunds,omitempty"`
+	RefundedAmount int      `json:"refunded_amount"`
+	NetAmount      int      `json:"net_amount"`
 }
 
 type Delivery struct {
@@ -57,3 +67,64 @@ type Item struct {
 	Brand       string `json:"brand" validate:"required"`
 	Status      int    `json:"status" validate:"required"`
 }
+
+// StatusUpdate asks to move an order to another lifecycle status. It is the
+// body of PATCH /api/order/{id}/status and of status-event messages.
+type StatusUpdate struct {
+	OrderUID string `json:"order_uid" validate:"required,uuid"`
+	Status   string `json:"status" validate:"required"`
+	Reason   string `json:"reason"`
+}
+
+type Refund struct {
+	// ChrtID is set for item refunds and empty for whole order refunds.
+	ChrtID    *int      `json:"chrt_id,omitempty"`
+	Amount    int       `json:"amount"`
+	Reason    string    `json:"reason,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// RefundRequest refunds the listed items or, when ChrtIDs is empty, the whole
+// amount not refunded yet.
+type RefundRequest struct {
+	OrderUID string `json:"order_uid" validate:"required,uuid"`
+	ChrtIDs  []int  `json:"chrt_ids"`
+	Reason   string `json:"reason" validate:"required"`
+}
+
+type StatusChange struct {
+	From      string    `json:"from"`
+	To        string    `json:"to"`
+	Reason    string    `json:"reason,omitempty"`
+	ChangedAt time.Time `json:"changed_at"`
+}
+
+type OrderFilter struct {
+	CustomerID      string `query:"customer_id"`
+	TrackNumber     string `query:"track_number"`
+	DeliveryService string `query:"delivery_service"`
+	CreatedFrom     string `query:"created_from"`
+	CreatedTo       string `query:"created_to"`
+	Currency        string `query:"currency"`
+	Provider        string `query:"provider"`
+	Brand           string `query:"brand"`
+	Cursor          string `query:"cursor"`
+	Limit           int    `query:"limit"`
+}
+
+type OrderPage struct {
+	Orders     []Order `json:"orders"`
+	NextCursor string  `json:"next_cursor,omitempty"`
+}
+
+type CacheStats struct {
+	Size          int     `json:"size"`
+	Hits          uint64  `json:"hits"`
+	Misses        uint64  `json:"misses"`
+	HitRatio      float64 `json:"hit_ratio"`
+	Evictions     uint64  `json:"evictions"`
+	LoadSuccesses uint64  `json:"load_successes"`
+	LoadFailures  uint64  `json:"load_failures"`
+	MissingSize   int     `json:"missing_size"`
+	SavedQueries  uint64  `json:"saved_queries"`
+}
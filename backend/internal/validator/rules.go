@@ -0,0 +1,95 @@
+package validator
+
+import (
+	"fmt"
+	"github.com/ilam072/wbtech-l0/backend/internal/types/dto"
+)
+
+type Action string
+
+const (
+	// ActionReject fails validation when the rule is broken.
+	ActionReject Action = "reject"
+	// ActionWarn only logs the broken rule and lets the order through.
+	ActionWarn Action = "warn"
+	// ActionOff disables the rule.
+	ActionOff Action = "off"
+)
+
+const (
+	RuleGoodsTotal     = "goods_total"
+	RuleAmount         = "amount"
+	RuleItemTotalPrice = "item_total_price"
+)
+
+// Check inspects an order that already passed the struct tag validation and
+// returns the fields breaking the rule. tolerance is the allowed difference
+// between compared monetary values.
+type Check func(order dto.Order, tolerance int) []FieldError
+
+type rule struct {
+	name   string
+	action Action
+	check  Check
+}
+
+// checkGoodsTotal requires payment.goods_total to equal the sum of item
+// total prices.
+func checkGoodsTotal(order dto.Order, tolerance int) []FieldError {
+	sum := 0
+	for _, item := range order.Items {
+		sum += item.TotalPrice
+	}
+
+	if withinTolerance(order.Payment.GoodsTotal, sum, tolerance) {
+		return nil
+	}
+	return []FieldError{{
+		Field:   "payment.goods_total",
+		Rule:    RuleGoodsTotal,
+		Message: fmt.Sprintf("must equal the sum of items[].total_price (%d), got %d", sum, order.Payment.GoodsTotal),
+	}}
+}
+
+// checkAmount requires payment.amount to equal goods_total + delivery_cost +
+// custom_fee.
+func checkAmount(order dto.Order, tolerance int) []FieldError {
+	p := order.Payment
+	expected := p.GoodsTotal + p.DeliveryCost + p.CustomFee
+
+	if withinTolerance(p.Amount, expected, tolerance) {
+		return nil
+	}
+	return []FieldError{{
+		Field:   "payment.amount",
+		Rule:    RuleAmount,
+		Message: fmt.Sprintf("must equal goods_total + delivery_cost + custom_fee (%d), got %d", expected, p.Amount),
+	}}
+}
+
+// checkItemTotalPrice requires every item total_price to equal its price
+// reduced by sale percent.
+func checkItemTotalPrice(order dto.Order, tolerance int) []FieldError {
+	var fields []FieldError
+	for i, item := range order.Items {
+		expected := item.Price * (100 - item.Sale) / 100
+
+		if withinTolerance(item.TotalPrice, expected, tolerance) {
+			continue
+		}
+		fields = append(fields, FieldError{
+			Field:   fmt.Sprintf("items[%d].total_price", i),
+			Rule:    RuleItemTotalPrice,
+			Message: fmt.Sprintf("must equal price minus sale%% (%d), got %d", expected, item.TotalPrice),
+		})
+	}
+	return fields
+}
+
+func withinTolerance(got, expected, tolerance int) bool {
+	diff := got - expected
+	if diff < 0 {
+		diff = -diff
+	}
+	return diff <= tolerance
+}
@@ -0,0 +1,126 @@
+package validator
+
+import (
+	"errors"
+	"github.com/ilam072/wbtech-l0/backend/internal/config"
+	"github.com/ilam072/wbtech-l0/backend/internal/types/dto"
+	"github.com/ilam072/wbtech-l0/backend/pkg/logger/handlers/slogdiscard"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func consistentOrder() dto.Order {
+	return dto.Order{
+		OrderUID:    "b563feb7-b2b8-4b6a-9f3e-5c1a2d3e4f50",
+		TrackNumber: "WBILMTESTTRACK",
+		Entry:       "WBIL",
+		Delivery: dto.Delivery{
+			Name:    "Test Testov",
+			Phone:   "+9720000000",
+			Zip:     "2639809",
+			City:    "Kiryat Mozkin",
+			Address: "Ploshad Mira 15",
+			Region:  "Kraiot",
+			Email:   "test@gmail.com",
+		},
+		Payment: dto.Payment{
+			Transaction:  "b563feb7b2b84b6test",
+			Currency:     "USD",
+			Provider:     "wbpay",
+			Amount:       1917 + 630,
+			PaymentDt:    1637907727,
+			Bank:         "alpha",
+			DeliveryCost: 1500,
+			GoodsTotal:   317 + 630,
+			CustomFee:    100,
+		},
+		Items: []dto.Item{
+			{ChrtID: 1, TrackNumber: "WBILMTESTTRACK", Price: 453, Rid: "rid1", Name: "Mascaras", Sale: 30, Size: "0", TotalPrice: 317, NmID: 1, Brand: "Vivienne Sabo", Status: 202},
+			{ChrtID: 2, TrackNumber: "WBILMTESTTRACK", Price: 700, Rid: "rid2", Name: "Lipstick", Sale: 10, Size: "0", TotalPrice: 630, NmID: 2, Brand: "Vivienne Sabo", Status: 202},
+		},
+		Locale:          "en",
+		CustomerID:      "test",
+		DeliveryService: "meest",
+		Shardkey:        "9",
+		SmID:            99,
+		OofShard:        "1",
+	}
+}
+
+func ruleFields(t *testing.T, err error) []FieldError {
+	t.Helper()
+
+	var verr *ValidationError
+	if !errors.As(err, &verr) {
+		t.Fatalf("expected *ValidationError, got %v", err)
+	}
+	return verr.Fields
+}
+
+func TestOrderValidator_Rules_Consistent(t *testing.T) {
+	v := New(slogdiscard.NewDiscardLogger(), config.ValidationConfig{})
+
+	assert.NoError(t, v.Validate(consistentOrder()))
+}
+
+func TestOrderValidator_Rules_Reject(t *testing.T) {
+	v := New(slogdiscard.NewDiscardLogger(), config.ValidationConfig{})
+
+	order := consistentOrder()
+	order.Items[1].TotalPrice = 600
+
+	fields := ruleFields(t, v.Validate(order))
+	assert.Equal(t, []string{RuleGoodsTotal, RuleItemTotalPrice}, []string{fields[0].Rule, fields[1].Rule})
+	assert.Equal(t, "payment.goods_total", fields[0].Field)
+	assert.Equal(t, "items[1].total_price", fields[1].Field)
+}
+
+func TestOrderValidator_Rules_Amount(t *testing.T) {
+	v := New(slogdiscard.NewDiscardLogger(), config.ValidationConfig{})
+
+	order := consistentOrder()
+	order.Payment.Amount += 50
+
+	fields := ruleFields(t, v.Validate(order))
+	assert.Equal(t, []FieldError{{
+		Field:   "payment.amount",
+		Rule:    RuleAmount,
+		Message: "must equal goods_total + delivery_cost + custom_fee (2547), got 2597",
+	}}, fields)
+}
+
+func TestOrderValidator_Rules_Tolerance(t *testing.T) {
+	v := New(slogdiscard.NewDiscardLogger(), config.ValidationConfig{Tolerance: 2})
+
+	order := consistentOrder()
+	order.Payment.Amount += 2
+
+	assert.NoError(t, v.Validate(order))
+}
+
+func TestOrderValidator_Rules_WarnAndOff(t *testing.T) {
+	v := New(slogdiscard.NewDiscardLogger(), config.ValidationConfig{
+		RuleActions: map[string]string{
+			RuleGoodsTotal:     string(ActionWarn),
+			RuleItemTotalPrice: string(ActionOff),
+		},
+	})
+
+	order := consistentOrder()
+	order.Items[1].TotalPrice = 600
+
+	assert.NoError(t, v.Validate(&order))
+}
+
+func TestOrderValidator_Register(t *testing.T) {
+	v := New(slogdiscard.NewDiscardLogger(), config.ValidationConfig{})
+	v.Register("ruble_only", func(order dto.Order, _ int) []FieldError {
+		if order.Payment.Currency == "RUB" {
+			return nil
+		}
+		return []FieldError{{Field: "payment.currency", Rule: "ruble_only", Message: "must be RUB"}}
+	})
+
+	fields := ruleFields(t, v.Validate(consistentOrder()))
+	assert.Equal(t, "ruble_only", fields[0].Rule)
+}
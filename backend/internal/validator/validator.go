@@ -1,14 +1,187 @@
 package validator
 
-import "github.com/go-playground/validator/v10"
+import (
+	"errors"
+	"fmt"
+	"github.com/go-playground/validator/v10"
+	"github.com/ilam072/wbtech-l0/backend/internal/config"
+	"github.com/ilam072/wbtech-l0/backend/internal/types/dto"
+	"log/slog"
+	"reflect"
+	"strings"
+)
+
+// FieldError describes a single failed rule. Field is the JSON path of the
+// value inside the validated payload, e.g. "items[2].sale".
+type FieldError struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+// ValidationError is returned by Validate when the payload breaks one or
+// more rules.
+type ValidationError struct {
+	Fields []FieldError
+}
+
+func (e *ValidationError) Error() string {
+	parts := make([]string, 0, len(e.Fields))
+	for _, f := range e.Fields {
+		parts = append(parts, f.Field+": "+f.Message)
+	}
+	return "validation failed: " + strings.Join(parts, "; ")
+}
 
 type OrderValidator struct {
-	validate *validator.Validate
+	log       *slog.Logger
+	validate  *validator.Validate
+	tolerance int
+	actions   map[string]string
+	rules     []rule
 }
 
-func New() *OrderValidator {
-	return &OrderValidator{validate: validator.New()}
+// New builds a validator checking struct tags and the built-in monetary
+// consistency rules of dto.Order.
+func New(log *slog.Logger, cfg config.ValidationConfig) *OrderValidator {
+	validate := validator.New()
+	validate.RegisterTagNameFunc(jsonName)
+
+	v := &OrderValidator{
+		log:       log,
+		validate:  validate,
+		tolerance: cfg.Tolerance,
+		actions:   cfg.RuleActions,
+	}
+
+	v.Register(RuleGoodsTotal, checkGoodsTotal)
+	v.Register(RuleAmount, checkAmount)
+	v.Register(RuleItemTotalPrice, checkItemTotalPrice)
+
+	return v
 }
+
+// Register adds a business rule applied to every dto.Order after the struct
+// tags pass. Whether the rule rejects or only warns is taken from the
+// configured rule actions.
+func (v *OrderValidator) Register(name string, check Check) {
+	action := Action(v.actions[name])
+
+	switch action {
+	case ActionReject, ActionWarn, ActionOff:
+	case "":
+		action = ActionReject
+	default:
+		v.log.Warn("unknown validation rule action, rejecting",
+			slog.String("rule", name),
+			slog.String("action", string(action)),
+		)
+		action = ActionReject
+	}
+
+	v.rules = append(v.rules, rule{name: name, action: action, check: check})
+}
+
 func (v *OrderValidator) Validate(i interface{}) error {
-	return v.validate.Struct(i)
+	if err := v.validate.Struct(i); err != nil {
+		return v.tagError(err)
+	}
+
+	switch order := i.(type) {
+	case dto.Order:
+		return v.applyRules(order)
+	case *dto.Order:
+		return v.applyRules(*order)
+	}
+	return nil
+}
+
+func (v *OrderValidator) applyRules(order dto.Order) error {
+	var rejected []FieldError
+
+	for _, r := range v.rules {
+		if r.action == ActionOff {
+			continue
+		}
+
+		fields := r.check(order, v.tolerance)
+		if len(fields) == 0 {
+			continue
+		}
+
+		if r.action == ActionWarn {
+			for _, f := range fields {
+				v.log.Warn("order breaks business rule",
+					slog.String("order_uid", order.OrderUID),
+					slog.String("rule", f.Rule),
+					slog.String("field", f.Field),
+					slog.String("message", f.Message),
+				)
+			}
+			continue
+		}
+		rejected = append(rejected, fields...)
+	}
+
+	if len(rejected) > 0 {
+		return &ValidationError{Fields: rejected}
+	}
+	return nil
+}
+
+func (v *OrderValidator) tagError(err error) error {
+	var fieldErrs validator.ValidationErrors
+	if !errors.As(err, &fieldErrs) {
+		return err
+	}
+
+	verr := &ValidationError{Fields: make([]FieldError, 0, len(fieldErrs))}
+	for _, fe := range fieldErrs {
+		verr.Fields = append(verr.Fields, FieldError{
+			Field:   fieldPath(fe.Namespace()),
+			Rule:    fe.Tag(),
+			Message: message(fe),
+		})
+	}
+	return verr
+}
+
+func jsonName(field reflect.StructField) string {
+	name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+	if name == "-" {
+		return ""
+	}
+	if name == "" {
+		return field.Name
+	}
+	return name
+}
+
+// fieldPath drops the root struct name from a validator namespace,
+// "Order.items[2].sale" becomes "items[2].sale".
+func fieldPath(namespace string) string {
+	if _, path, ok := strings.Cut(namespace, "."); ok {
+		return path
+	}
+	return namespace
+}
+
+func message(fe validator.FieldError) string {
+	switch fe.Tag() {
+	case "required":
+		return "is required"
+	case "email":
+		return "must be a valid email address"
+	case "uuid":
+		return "must be a valid UUID"
+	case "min":
+		if fe.Kind() == reflect.Slice {
+			return fmt.Sprintf("must contain at least %s element(s)", fe.Param())
+		}
+		return fmt.Sprintf("must be at least %s", fe.Param())
+	}
+	if fe.Param() != "" {
+		return fmt.Sprintf("must satisfy %s=%s", fe.Tag(), fe.Param())
+	}
+	return fmt.Sprintf("must satisfy %s", fe.Tag())
 }
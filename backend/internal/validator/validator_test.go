@@ -0,0 +1,48 @@
+package validator
+
+import (
+	"errors"
+	"github.com/ilam072/wbtech-l0/backend/internal/config"
+	"github.com/ilam072/wbtech-l0/backend/pkg/logger/handlers/slogdiscard"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+type testItem struct {
+	Sale int `json:"sale" validate:"required"`
+}
+
+type testOrder struct {
+	OrderUID string     `json:"order_uid" validate:"required,uuid"`
+	Email    string     `json:"email" validate:"required,email"`
+	Items    []testItem `json:"items" validate:"required,min=1,dive"`
+}
+
+func TestOrderValidator_Validate_FieldErrors(t *testing.T) {
+	v := New(slogdiscard.NewDiscardLogger(), config.ValidationConfig{})
+
+	err := v.Validate(testOrder{
+		OrderUID: "not-a-uuid",
+		Email:    "test@gmail.com",
+		Items:    []testItem{{Sale: 30}, {Sale: 10}, {}},
+	})
+
+	var verr *ValidationError
+	assert.True(t, errors.As(err, &verr))
+	assert.Equal(t, []FieldError{
+		{Field: "order_uid", Rule: "uuid", Message: "must be a valid UUID"},
+		{Field: "items[2].sale", Rule: "required", Message: "is required"},
+	}, verr.Fields)
+	assert.Equal(t, "validation failed: order_uid: must be a valid UUID; items[2].sale: is required", err.Error())
+}
+
+func TestOrderValidator_Validate_Valid(t *testing.T) {
+	v := New(slogdiscard.NewDiscardLogger(), config.ValidationConfig{})
+
+	err := v.Validate(testOrder{
+		OrderUID: "b563feb7-b2b8-4b6a-9f3e-5c1a2d3e4f50",
+		Email:    "test@gmail.com",
+		Items:    []testItem{{Sale: 30}},
+	})
+	assert.NoError(t, err)
+}
@@ -13,6 +13,8 @@ import (
 	context "context"
 	reflect "reflect"
 
+	health "github.com/ilam072/wbtech-l0/backend/internal/health"
+	service "github.com/ilam072/wbtech-l0/backend/internal/service"
 	dto "github.com/ilam072/wbtech-l0/backend/internal/types/dto"
 	gomock "go.uber.org/mock/gomock"
 )
@@ -41,6 +43,65 @@ func (m *MockOrderService) EXPECT() *MockOrderServiceMockRecorder {
 	return m.recorder
 }
 
+// CancelOrder mocks base method.
+func (m *MockOrderService) CancelOrder(ctx context.Context, orderId, reason string) (dto.Order, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CancelOrder", ctx, orderId, reason)
+	ret0, _ := ret[0].(dto.Order)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CancelOrder indicates an expected call of CancelOrder.
+func (mr *MockOrderServiceMockRecorder) CancelOrder(ctx, orderId, reason any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelOrder", reflect.TypeOf((*MockOrderService)(nil).CancelOrder), ctx, orderId, reason)
+}
+
+// CreateOrder mocks base method.
+func (m *MockOrderService) CreateOrder(ctx context.Context, order dto.Order) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateOrder", ctx, order)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateOrder indicates an expected call of CreateOrder.
+func (mr *MockOrderServiceMockRecorder) CreateOrder(ctx, order any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateOrder", reflect.TypeOf((*MockOrderService)(nil).CreateOrder), ctx, order)
+}
+
+// CreateOrders mocks base method.
+func (m *MockOrderService) CreateOrders(ctx context.Context, orders []dto.Order) ([]service.CreateResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateOrders", ctx, orders)
+	ret0, _ := ret[0].([]service.CreateResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateOrders indicates an expected call of CreateOrders.
+func (mr *MockOrderServiceMockRecorder) CreateOrders(ctx, orders any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateOrders", reflect.TypeOf((*MockOrderService)(nil).CreateOrders), ctx, orders)
+}
+
+// GetCustomerOrders mocks base method.
+func (m *MockOrderService) GetCustomerOrders(ctx context.Context, customerID string, filter dto.OrderFilter) (dto.OrderPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCustomerOrders", ctx, customerID, filter)
+	ret0, _ := ret[0].(dto.OrderPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCustomerOrders indicates an expected call of GetCustomerOrders.
+func (mr *MockOrderServiceMockRecorder) GetCustomerOrders(ctx, customerID, filter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCustomerOrders", reflect.TypeOf((*MockOrderService)(nil).GetCustomerOrders), ctx, customerID, filter)
+}
+
 // GetOrder mocks base method.
 func (m *MockOrderService) GetOrder(ctx context.Context, orderId string) (dto.Order, error) {
 	m.ctrl.T.Helper()
@@ -55,3 +116,247 @@ func (mr *MockOrderServiceMockRecorder) GetOrder(ctx, orderId any) *gomock.Call
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrder", reflect.TypeOf((*MockOrderService)(nil).GetOrder), ctx, orderId)
 }
+
+// GetOrderByTrackNumber mocks base method.
+func (m *MockOrderService) GetOrderByTrackNumber(ctx context.Context, trackNumber string) (dto.Order, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOrderByTrackNumber", ctx, trackNumber)
+	ret0, _ := ret[0].(dto.Order)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOrderByTrackNumber indicates an expected call of GetOrderByTrackNumber.
+func (mr *MockOrderServiceMockRecorder) GetOrderByTrackNumber(ctx, trackNumber any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrderByTrackNumber", reflect.TypeOf((*MockOrderService)(nil).GetOrderByTrackNumber), ctx, trackNumber)
+}
+
+// GetOrderStatusHistory mocks base method.
+func (m *MockOrderService) GetOrderStatusHistory(ctx context.Context, orderId string) ([]dto.StatusChange, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOrderStatusHistory", ctx, orderId)
+	ret0, _ := ret[0].([]dto.StatusChange)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOrderStatusHistory indicates an expected call of GetOrderStatusHistory.
+func (mr *MockOrderServiceMockRecorder) GetOrderStatusHistory(ctx, orderId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrderStatusHistory", reflect.TypeOf((*MockOrderService)(nil).GetOrderStatusHistory), ctx, orderId)
+}
+
+// ListOrders mocks base method.
+func (m *MockOrderService) ListOrders(ctx context.Context, filter dto.OrderFilter) (dto.OrderPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListOrders", ctx, filter)
+	ret0, _ := ret[0].(dto.OrderPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListOrders indicates an expected call of ListOrders.
+func (mr *MockOrderServiceMockRecorder) ListOrders(ctx, filter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListOrders", reflect.TypeOf((*MockOrderService)(nil).ListOrders), ctx, filter)
+}
+
+// RefundOrder mocks base method.
+func (m *MockOrderService) RefundOrder(ctx context.Context, request dto.RefundRequest) (dto.Order, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RefundOrder", ctx, request)
+	ret0, _ := ret[0].(dto.Order)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RefundOrder indicates an expected call of RefundOrder.
+func (mr *MockOrderServiceMockRecorder) RefundOrder(ctx, request any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RefundOrder", reflect.TypeOf((*MockOrderService)(nil).RefundOrder), ctx, request)
+}
+
+// UpdateOrderStatus mocks base method.
+func (m *MockOrderService) UpdateOrderStatus(ctx context.Context, update dto.StatusUpdate) (dto.Order, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateOrderStatus", ctx, update)
+	ret0, _ := ret[0].(dto.Order)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateOrderStatus indicates an expected call of UpdateOrderStatus.
+func (mr *MockOrderServiceMockRecorder) UpdateOrderStatus(ctx, update any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateOrderStatus", reflect.TypeOf((*MockOrderService)(nil).UpdateOrderStatus), ctx, update)
+}
+
+// UpsertOrder mocks base method.
+func (m *MockOrderService) UpsertOrder(ctx context.Context, order dto.Order) (service.UpsertOutcome, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertOrder", ctx, order)
+	ret0, _ := ret[0].(service.UpsertOutcome)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpsertOrder indicates an expected call of UpsertOrder.
+func (mr *MockOrderServiceMockRecorder) UpsertOrder(ctx, order any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertOrder", reflect.TypeOf((*MockOrderService)(nil).UpsertOrder), ctx, order)
+}
+
+// MockValidator is a mock of Validator interface.
+type MockValidator struct {
+	ctrl     *gomock.Controller
+	recorder *MockValidatorMockRecorder
+	isgomock struct{}
+}
+
+// MockValidatorMockRecorder is the mock recorder for MockValidator.
+type MockValidatorMockRecorder struct {
+	mock *MockValidator
+}
+
+// NewMockValidator creates a new mock instance.
+func NewMockValidator(ctrl *gomock.Controller) *MockValidator {
+	mock := &MockValidator{ctrl: ctrl}
+	mock.recorder = &MockValidatorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockValidator) EXPECT() *MockValidatorMockRecorder {
+	return m.recorder
+}
+
+// Validate mocks base method.
+func (m *MockValidator) Validate(i any) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Validate", i)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Validate indicates an expected call of Validate.
+func (mr *MockValidatorMockRecorder) Validate(i any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Validate", reflect.TypeOf((*MockValidator)(nil).Validate), i)
+}
+
+// MockHealthChecker is a mock of HealthChecker interface.
+type MockHealthChecker struct {
+	ctrl     *gomock.Controller
+	recorder *MockHealthCheckerMockRecorder
+	isgomock struct{}
+}
+
+// MockHealthCheckerMockRecorder is the mock recorder for MockHealthChecker.
+type MockHealthCheckerMockRecorder struct {
+	mock *MockHealthChecker
+}
+
+// NewMockHealthChecker creates a new mock instance.
+func NewMockHealthChecker(ctrl *gomock.Controller) *MockHealthChecker {
+	mock := &MockHealthChecker{ctrl: ctrl}
+	mock.recorder = &MockHealthCheckerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockHealthChecker) EXPECT() *MockHealthCheckerMockRecorder {
+	return m.recorder
+}
+
+// Ready mocks base method.
+func (m *MockHealthChecker) Ready(ctx context.Context) health.Report {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Ready", ctx)
+	ret0, _ := ret[0].(health.Report)
+	return ret0
+}
+
+// Ready indicates an expected call of Ready.
+func (mr *MockHealthCheckerMockRecorder) Ready(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Ready", reflect.TypeOf((*MockHealthChecker)(nil).Ready), ctx)
+}
+
+// MockCacheAdmin is a mock of CacheAdmin interface.
+type MockCacheAdmin struct {
+	ctrl     *gomock.Controller
+	recorder *MockCacheAdminMockRecorder
+	isgomock struct{}
+}
+
+// MockCacheAdminMockRecorder is the mock recorder for MockCacheAdmin.
+type MockCacheAdminMockRecorder struct {
+	mock *MockCacheAdmin
+}
+
+// NewMockCacheAdmin creates a new mock instance.
+func NewMockCacheAdmin(ctrl *gomock.Controller) *MockCacheAdmin {
+	mock := &MockCacheAdmin{ctrl: ctrl}
+	mock.recorder = &MockCacheAdminMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCacheAdmin) EXPECT() *MockCacheAdminMockRecorder {
+	return m.recorder
+}
+
+// Invalidate mocks base method.
+func (m *MockCacheAdmin) Invalidate(key string) bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Invalidate", key)
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// Invalidate indicates an expected call of Invalidate.
+func (mr *MockCacheAdminMockRecorder) Invalidate(key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Invalidate", reflect.TypeOf((*MockCacheAdmin)(nil).Invalidate), key)
+}
+
+// Preload mocks base method.
+func (m *MockCacheAdmin) Preload(ctx context.Context, limit int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Preload", ctx, limit)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Preload indicates an expected call of Preload.
+func (mr *MockCacheAdminMockRecorder) Preload(ctx, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Preload", reflect.TypeOf((*MockCacheAdmin)(nil).Preload), ctx, limit)
+}
+
+// Purge mocks base method.
+func (m *MockCacheAdmin) Purge() {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Purge")
+}
+
+// Purge indicates an expected call of Purge.
+func (mr *MockCacheAdminMockRecorder) Purge() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Purge", reflect.TypeOf((*MockCacheAdmin)(nil).Purge))
+}
+
+// Stats mocks base method.
+func (m *MockCacheAdmin) Stats() dto.CacheStats {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Stats")
+	ret0, _ := ret[0].(dto.CacheStats)
+	return ret0
+}
+
+// Stats indicates an expected call of Stats.
+func (mr *MockCacheAdminMockRecorder) Stats() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Stats", reflect.TypeOf((*MockCacheAdmin)(nil).Stats))
+}
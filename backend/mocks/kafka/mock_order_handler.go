@@ -13,6 +13,8 @@ import (
 	context "context"
 	reflect "reflect"
 
+	dlq "github.com/ilam072/wbtech-l0/backend/internal/broker/kafka/dlq"
+	service "github.com/ilam072/wbtech-l0/backend/internal/service"
 	dto "github.com/ilam072/wbtech-l0/backend/internal/types/dto"
 	kafka "github.com/segmentio/kafka-go"
 	gomock "go.uber.org/mock/gomock"
@@ -56,19 +58,38 @@ func (mr *MockConsumerMockRecorder) Close() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockConsumer)(nil).Close))
 }
 
-// Consume mocks base method.
-func (m *MockConsumer) Consume(arg0 context.Context) (kafka.Message, error) {
+// Commit mocks base method.
+func (m *MockConsumer) Commit(arg0 context.Context, arg1 ...kafka.Message) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Consume", arg0)
+	varargs := []any{arg0}
+	for _, a := range arg1 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Commit", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Commit indicates an expected call of Commit.
+func (mr *MockConsumerMockRecorder) Commit(arg0 any, arg1 ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{arg0}, arg1...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Commit", reflect.TypeOf((*MockConsumer)(nil).Commit), varargs...)
+}
+
+// Fetch mocks base method.
+func (m *MockConsumer) Fetch(arg0 context.Context) (kafka.Message, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Fetch", arg0)
 	ret0, _ := ret[0].(kafka.Message)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// Consume indicates an expected call of Consume.
-func (mr *MockConsumerMockRecorder) Consume(arg0 any) *gomock.Call {
+// Fetch indicates an expected call of Fetch.
+func (mr *MockConsumerMockRecorder) Fetch(arg0 any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Consume", reflect.TypeOf((*MockConsumer)(nil).Consume), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Fetch", reflect.TypeOf((*MockConsumer)(nil).Fetch), arg0)
 }
 
 // MockService is a mock of Service interface.
@@ -95,18 +116,19 @@ func (m *MockService) EXPECT() *MockServiceMockRecorder {
 	return m.recorder
 }
 
-// CreateOrder mocks base method.
-func (m *MockService) CreateOrder(arg0 context.Context, arg1 dto.Order) error {
+// UpsertOrder mocks base method.
+func (m *MockService) UpsertOrder(arg0 context.Context, arg1 dto.Order) (service.UpsertOutcome, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateOrder", arg0, arg1)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret := m.ctrl.Call(m, "UpsertOrder", arg0, arg1)
+	ret0, _ := ret[0].(service.UpsertOutcome)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
-// CreateOrder indicates an expected call of CreateOrder.
-func (mr *MockServiceMockRecorder) CreateOrder(arg0, arg1 any) *gomock.Call {
+// UpsertOrder indicates an expected call of UpsertOrder.
+func (mr *MockServiceMockRecorder) UpsertOrder(arg0, arg1 any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateOrder", reflect.TypeOf((*MockService)(nil).CreateOrder), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertOrder", reflect.TypeOf((*MockService)(nil).UpsertOrder), arg0, arg1)
 }
 
 // MockValidator is a mock of Validator interface.
@@ -146,3 +168,41 @@ func (mr *MockValidatorMockRecorder) Validate(i any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Validate", reflect.TypeOf((*MockValidator)(nil).Validate), i)
 }
+
+// MockDeadLetterProducer is a mock of DeadLetterProducer interface.
+type MockDeadLetterProducer struct {
+	ctrl     *gomock.Controller
+	recorder *MockDeadLetterProducerMockRecorder
+	isgomock struct{}
+}
+
+// MockDeadLetterProducerMockRecorder is the mock recorder for MockDeadLetterProducer.
+type MockDeadLetterProducerMockRecorder struct {
+	mock *MockDeadLetterProducer
+}
+
+// NewMockDeadLetterProducer creates a new mock instance.
+func NewMockDeadLetterProducer(ctrl *gomock.Controller) *MockDeadLetterProducer {
+	mock := &MockDeadLetterProducer{ctrl: ctrl}
+	mock.recorder = &MockDeadLetterProducerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDeadLetterProducer) EXPECT() *MockDeadLetterProducerMockRecorder {
+	return m.recorder
+}
+
+// Publish mocks base method.
+func (m *MockDeadLetterProducer) Publish(ctx context.Context, msg kafka.Message, f dlq.Failure) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Publish", ctx, msg, f)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Publish indicates an expected call of Publish.
+func (mr *MockDeadLetterProducerMockRecorder) Publish(ctx, msg, f any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Publish", reflect.TypeOf((*MockDeadLetterProducer)(nil).Publish), ctx, msg, f)
+}
@@ -0,0 +1,57 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: status_handler.go
+//
+// Generated by this command:
+//
+//	mockgen -source=status_handler.go -destination=../../../../mocks/kafka/mock_status_handler.go -package kafka
+//
+
+// Package kafka is a generated GoMock package.
+package kafka
+
+import (
+	context "context"
+	reflect "reflect"
+
+	dto "github.com/ilam072/wbtech-l0/backend/internal/types/dto"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockStatusService is a mock of StatusService interface.
+type MockStatusService struct {
+	ctrl     *gomock.Controller
+	recorder *MockStatusServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockStatusServiceMockRecorder is the mock recorder for MockStatusService.
+type MockStatusServiceMockRecorder struct {
+	mock *MockStatusService
+}
+
+// NewMockStatusService creates a new mock instance.
+func NewMockStatusService(ctrl *gomock.Controller) *MockStatusService {
+	mock := &MockStatusService{ctrl: ctrl}
+	mock.recorder = &MockStatusServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockStatusService) EXPECT() *MockStatusServiceMockRecorder {
+	return m.recorder
+}
+
+// UpdateOrderStatus mocks base method.
+func (m *MockStatusService) UpdateOrderStatus(ctx context.Context, update dto.StatusUpdate) (dto.Order, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateOrderStatus", ctx, update)
+	ret0, _ := ret[0].(dto.Order)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateOrderStatus indicates an expected call of UpdateOrderStatus.
+func (mr *MockStatusServiceMockRecorder) UpdateOrderStatus(ctx, update any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateOrderStatus", reflect.TypeOf((*MockStatusService)(nil).UpdateOrderStatus), ctx, update)
+}
@@ -13,6 +13,7 @@ import (
 	context "context"
 	reflect "reflect"
 
+	repo "github.com/ilam072/wbtech-l0/backend/internal/repo"
 	domain "github.com/ilam072/wbtech-l0/backend/internal/types/domain"
 	dto "github.com/ilam072/wbtech-l0/backend/internal/types/dto"
 	gomock "go.uber.org/mock/gomock"
@@ -42,6 +43,20 @@ func (m *MockOrderRepo) EXPECT() *MockOrderRepoMockRecorder {
 	return m.recorder
 }
 
+// CancelOrder mocks base method.
+func (m *MockOrderRepo) CancelOrder(ctx context.Context, change domain.StatusChange, refunds []domain.Refund) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CancelOrder", ctx, change, refunds)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CancelOrder indicates an expected call of CancelOrder.
+func (mr *MockOrderRepoMockRecorder) CancelOrder(ctx, change, refunds any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelOrder", reflect.TypeOf((*MockOrderRepo)(nil).CancelOrder), ctx, change, refunds)
+}
+
 // CreateOrder mocks base method.
 func (m *MockOrderRepo) CreateOrder(arg0 context.Context, arg1 domain.Order, arg2 domain.Delivery, arg3 domain.Payment, arg4 []domain.Item) error {
 	m.ctrl.T.Helper()
@@ -56,6 +71,35 @@ func (mr *MockOrderRepoMockRecorder) CreateOrder(arg0, arg1, arg2, arg3, arg4 an
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateOrder", reflect.TypeOf((*MockOrderRepo)(nil).CreateOrder), arg0, arg1, arg2, arg3, arg4)
 }
 
+// CreateOrders mocks base method.
+func (m *MockOrderRepo) CreateOrders(ctx context.Context, orders []domain.FullOrder) ([]repo.CreateResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateOrders", ctx, orders)
+	ret0, _ := ret[0].([]repo.CreateResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateOrders indicates an expected call of CreateOrders.
+func (mr *MockOrderRepoMockRecorder) CreateOrders(ctx, orders any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateOrders", reflect.TypeOf((*MockOrderRepo)(nil).CreateOrders), ctx, orders)
+}
+
+// CreateRefunds mocks base method.
+func (m *MockOrderRepo) CreateRefunds(ctx context.Context, status domain.OrderStatus, refunds []domain.Refund) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateRefunds", ctx, status, refunds)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateRefunds indicates an expected call of CreateRefunds.
+func (mr *MockOrderRepoMockRecorder) CreateRefunds(ctx, status, refunds any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateRefunds", reflect.TypeOf((*MockOrderRepo)(nil).CreateRefunds), ctx, status, refunds)
+}
+
 // GetOrder mocks base method.
 func (m *MockOrderRepo) GetOrder(ctx context.Context, ID string) (domain.FullOrder, error) {
 	m.ctrl.T.Helper()
@@ -71,6 +115,80 @@ func (mr *MockOrderRepoMockRecorder) GetOrder(ctx, ID any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrder", reflect.TypeOf((*MockOrderRepo)(nil).GetOrder), ctx, ID)
 }
 
+// GetOrderByTrackNumber mocks base method.
+func (m *MockOrderRepo) GetOrderByTrackNumber(ctx context.Context, trackNumber string) (domain.FullOrder, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOrderByTrackNumber", ctx, trackNumber)
+	ret0, _ := ret[0].(domain.FullOrder)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOrderByTrackNumber indicates an expected call of GetOrderByTrackNumber.
+func (mr *MockOrderRepoMockRecorder) GetOrderByTrackNumber(ctx, trackNumber any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrderByTrackNumber", reflect.TypeOf((*MockOrderRepo)(nil).GetOrderByTrackNumber), ctx, trackNumber)
+}
+
+// GetStatusHistory mocks base method.
+func (m *MockOrderRepo) GetStatusHistory(ctx context.Context, orderID string) ([]domain.StatusChange, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetStatusHistory", ctx, orderID)
+	ret0, _ := ret[0].([]domain.StatusChange)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetStatusHistory indicates an expected call of GetStatusHistory.
+func (mr *MockOrderRepoMockRecorder) GetStatusHistory(ctx, orderID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStatusHistory", reflect.TypeOf((*MockOrderRepo)(nil).GetStatusHistory), ctx, orderID)
+}
+
+// ListOrders mocks base method.
+func (m *MockOrderRepo) ListOrders(ctx context.Context, filter domain.OrderFilter) ([]domain.FullOrder, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListOrders", ctx, filter)
+	ret0, _ := ret[0].([]domain.FullOrder)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListOrders indicates an expected call of ListOrders.
+func (mr *MockOrderRepoMockRecorder) ListOrders(ctx, filter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListOrders", reflect.TypeOf((*MockOrderRepo)(nil).ListOrders), ctx, filter)
+}
+
+// UpdateOrderStatus mocks base method.
+func (m *MockOrderRepo) UpdateOrderStatus(ctx context.Context, change domain.StatusChange) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateOrderStatus", ctx, change)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateOrderStatus indicates an expected call of UpdateOrderStatus.
+func (mr *MockOrderRepoMockRecorder) UpdateOrderStatus(ctx, change any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateOrderStatus", reflect.TypeOf((*MockOrderRepo)(nil).UpdateOrderStatus), ctx, change)
+}
+
+// UpsertOrder mocks base method.
+func (m *MockOrderRepo) UpsertOrder(ctx context.Context, order domain.FullOrder) (repo.CreateOutcome, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertOrder", ctx, order)
+	ret0, _ := ret[0].(repo.CreateOutcome)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpsertOrder indicates an expected call of UpsertOrder.
+func (mr *MockOrderRepoMockRecorder) UpsertOrder(ctx, order any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertOrder", reflect.TypeOf((*MockOrderRepo)(nil).UpsertOrder), ctx, order)
+}
+
 // MockOrderCache is a mock of OrderCache interface.
 type MockOrderCache struct {
 	ctrl     *gomock.Controller
@@ -95,19 +213,48 @@ func (m *MockOrderCache) EXPECT() *MockOrderCacheMockRecorder {
 	return m.recorder
 }
 
-// Get mocks base method.
-func (m *MockOrderCache) Get(key string) (dto.Order, bool) {
+// GetByTrackNumber mocks base method.
+func (m *MockOrderCache) GetByTrackNumber(trackNumber string) (dto.Order, bool) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Get", key)
+	ret := m.ctrl.Call(m, "GetByTrackNumber", trackNumber)
 	ret0, _ := ret[0].(dto.Order)
 	ret1, _ := ret[1].(bool)
 	return ret0, ret1
 }
 
-// Get indicates an expected call of Get.
-func (mr *MockOrderCacheMockRecorder) Get(key any) *gomock.Call {
+// GetByTrackNumber indicates an expected call of GetByTrackNumber.
+func (mr *MockOrderCacheMockRecorder) GetByTrackNumber(trackNumber any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByTrackNumber", reflect.TypeOf((*MockOrderCache)(nil).GetByTrackNumber), trackNumber)
+}
+
+// Invalidate mocks base method.
+func (m *MockOrderCache) Invalidate(key string) bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Invalidate", key)
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// Invalidate indicates an expected call of Invalidate.
+func (mr *MockOrderCacheMockRecorder) Invalidate(key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Invalidate", reflect.TypeOf((*MockOrderCache)(nil).Invalidate), key)
+}
+
+// Load mocks base method.
+func (m *MockOrderCache) Load(ctx context.Context, key string) (dto.Order, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Load", ctx, key)
+	ret0, _ := ret[0].(dto.Order)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Load indicates an expected call of Load.
+func (mr *MockOrderCacheMockRecorder) Load(ctx, key any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockOrderCache)(nil).Get), key)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Load", reflect.TypeOf((*MockOrderCache)(nil).Load), ctx, key)
 }
 
 // Set mocks base method.
@@ -0,0 +1,73 @@
+package retry
+
+import (
+	"context"
+	"math"
+	"math/rand/v2"
+	"time"
+)
+
+type Policy struct {
+	MaxAttempts    int
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+	Multiplier     float64
+}
+
+// Backoff returns the jittered delay to wait after the given failed attempt
+// (starting from 1). Half of the exponential delay is fixed and the other half
+// is random, so the delay still grows while concurrent retries spread out.
+func (p Policy) Backoff(attempt int) time.Duration {
+	if attempt < 1 || p.InitialBackoff <= 0 {
+		return 0
+	}
+
+	multiplier := p.Multiplier
+	if multiplier < 1 {
+		multiplier = 1
+	}
+
+	delay := float64(p.InitialBackoff) * math.Pow(multiplier, float64(attempt-1))
+	if p.MaxBackoff > 0 && delay > float64(p.MaxBackoff) {
+		delay = float64(p.MaxBackoff)
+	}
+
+	half := time.Duration(delay / 2)
+	return half + rand.N(half+1)
+}
+
+// Do calls fn until it succeeds, returns an error rejected by retryable,
+// the attempt budget is exhausted or ctx is done. It returns the number of
+// attempts made and the last error.
+func (p Policy) Do(ctx context.Context, retryable func(error) bool, fn func(context.Context) error) (int, error) {
+	maxAttempts := p.MaxAttempts
+	if maxAttempts < 1 {
+		maxAttempts = 1
+	}
+
+	var err error
+	for attempt := 1; ; attempt++ {
+		err = fn(ctx)
+		if err == nil || !retryable(err) || attempt >= maxAttempts {
+			return attempt, err
+		}
+
+		if !Wait(ctx, p.Backoff(attempt)) {
+			return attempt, err
+		}
+	}
+}
+
+// Wait blocks for d or until ctx is done and reports whether the whole delay
+// has passed.
+func Wait(ctx context.Context, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
@@ -0,0 +1,94 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+var errTemporary = errors.New("temporary")
+
+func isTemporary(err error) bool {
+	return errors.Is(err, errTemporary)
+}
+
+func TestPolicy_Backoff(t *testing.T) {
+	p := Policy{
+		InitialBackoff: 100 * time.Millisecond,
+		MaxBackoff:     time.Second,
+		Multiplier:     2,
+	}
+
+	cases := []struct {
+		attempt  int
+		min, max time.Duration
+	}{
+		{attempt: 1, min: 50 * time.Millisecond, max: 100 * time.Millisecond},
+		{attempt: 2, min: 100 * time.Millisecond, max: 200 * time.Millisecond},
+		{attempt: 3, min: 200 * time.Millisecond, max: 400 * time.Millisecond},
+		{attempt: 10, min: 500 * time.Millisecond, max: time.Second},
+	}
+
+	for _, c := range cases {
+		for i := 0; i < 100; i++ {
+			d := p.Backoff(c.attempt)
+			assert.GreaterOrEqual(t, d, c.min)
+			assert.LessOrEqual(t, d, c.max)
+		}
+	}
+}
+
+func TestPolicy_Do_RetriesTemporaryErrors(t *testing.T) {
+	p := Policy{MaxAttempts: 5, InitialBackoff: time.Millisecond, Multiplier: 2}
+
+	calls := 0
+	attempts, err := p.Do(context.Background(), isTemporary, func(context.Context) error {
+		calls++
+		if calls < 3 {
+			return errTemporary
+		}
+		return nil
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 3, attempts)
+	assert.Equal(t, 3, calls)
+}
+
+func TestPolicy_Do_StopsOnPermanentError(t *testing.T) {
+	p := Policy{MaxAttempts: 5, InitialBackoff: time.Millisecond}
+	permanent := errors.New("permanent")
+
+	attempts, err := p.Do(context.Background(), isTemporary, func(context.Context) error {
+		return permanent
+	})
+
+	assert.ErrorIs(t, err, permanent)
+	assert.Equal(t, 1, attempts)
+}
+
+func TestPolicy_Do_ExhaustsBudget(t *testing.T) {
+	p := Policy{MaxAttempts: 3, InitialBackoff: time.Millisecond}
+
+	attempts, err := p.Do(context.Background(), isTemporary, func(context.Context) error {
+		return errTemporary
+	})
+
+	assert.ErrorIs(t, err, errTemporary)
+	assert.Equal(t, 3, attempts)
+}
+
+func TestPolicy_Do_StopsWhenContextDone(t *testing.T) {
+	p := Policy{MaxAttempts: 10, InitialBackoff: time.Hour}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	attempts, err := p.Do(ctx, isTemporary, func(context.Context) error {
+		return errTemporary
+	})
+
+	assert.ErrorIs(t, err, errTemporary)
+	assert.Equal(t, 1, attempts)
+}
@@ -15,31 +15,794 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
+        "/api/admin/cache": {
+            "get": {
+                "description": "Returns hit, miss and eviction counters and the current size of the order cache",
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Cache statistics",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "admin token",
+                        "name": "X-Admin-Token",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.CacheStats"
+                        }
+                    },
+                    "401": {
+                        "description": "invalid admin token",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResp"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Evicts every order from the cache",
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Purge cache",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "admin token",
+                        "name": "X-Admin-Token",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "401": {
+                        "description": "invalid admin token",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResp"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/admin/cache/orders/{id}": {
+            "delete": {
+                "description": "Evicts a single order from the cache, the next read loads it from the database",
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Evict cached order",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "admin token",
+                        "name": "X-Admin-Token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "order uid",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "401": {
+                        "description": "invalid admin token",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResp"
+                        }
+                    },
+                    "404": {
+                        "description": "order is not cached",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResp"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/admin/cache/preload": {
+            "post": {
+                "description": "Loads the latest orders from the database into the cache",
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Preload cache",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "admin token",
+                        "name": "X-Admin-Token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "number of latest orders to load",
+                        "name": "limit",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.CacheStats"
+                        }
+                    },
+                    "400": {
+                        "description": "invalid limit",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResp"
+                        }
+                    },
+                    "401": {
+                        "description": "invalid admin token",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResp"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResp"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/customers/{id}/orders": {
+            "get": {
+                "description": "Returns orders of the given customer, newest first. Use next_cursor from the response to fetch the next page.",
+                "tags": [
+                    "order"
+                ],
+                "summary": "List customer orders",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "customer id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "page size (1-100, default 20)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "next_cursor of the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.OrderPage"
+                        }
+                    },
+                    "400": {
+                        "description": "invalid filter",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResp"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResp"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/order/by-track/{track}": {
+            "get": {
+                "description": "Returns order details by given track number",
+                "tags": [
+                    "order"
+                ],
+                "summary": "Get order by track number",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "track number",
+                        "name": "track",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Order"
+                        }
+                    },
+                    "404": {
+                        "description": "order not found",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResp"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResp"
+                        }
+                    }
+                }
+            }
+        },
         "/api/order/{id}": {
             "get": {
                 "description": "Returns order details by given ID",
                 "tags": [
                     "order"
                 ],
-                "summary": "Get order by ID",
+                "summary": "Get order by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "order uid",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Order"
+                        }
+                    },
+                    "404": {
+                        "description": "order not found",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResp"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResp"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/order/{id}/cancel": {
+            "post": {
+                "description": "Cancels an order that has not been shipped yet. Paid orders get the amount not refunded yet\nrefunded in the same step. Cancelling a cancelled order is a no-op.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "order"
+                ],
+                "summary": "Cancel order",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "order uid",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "cancellation reason",
+                        "name": "cancel",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/rest.CancelReq"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Order"
+                        }
+                    },
+                    "400": {
+                        "description": "malformed body or invalid uuid",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResp"
+                        }
+                    },
+                    "404": {
+                        "description": "order not found",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResp"
+                        }
+                    },
+                    "409": {
+                        "description": "order cannot be cancelled or status changed concurrently",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResp"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResp"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/order/{id}/history": {
+            "get": {
+                "description": "Returns the status changes of an order, oldest first",
+                "tags": [
+                    "order"
+                ],
+                "summary": "Get order status history",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "order uid",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/dto.StatusChange"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "invalid uuid",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResp"
+                        }
+                    },
+                    "404": {
+                        "description": "order not found",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResp"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResp"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/order/{id}/refunds": {
+            "post": {
+                "description": "Refunds the listed items by their total price or, without chrt_ids, the whole amount not refunded yet.\nOnly paid, assembled, shipped, delivered and returned orders can be refunded.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "order"
+                ],
+                "summary": "Refund order",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "order uid",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "refund",
+                        "name": "refund",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/rest.RefundReq"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Order"
+                        }
+                    },
+                    "400": {
+                        "description": "malformed body",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResp"
+                        }
+                    },
+                    "404": {
+                        "description": "order not found",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResp"
+                        }
+                    },
+                    "409": {
+                        "description": "order cannot be refunded, already refunded or changed concurrently",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResp"
+                        }
+                    },
+                    "422": {
+                        "description": "validation failed, unknown or free item, or refund exceeds payment",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ValidationErrorResp"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResp"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/order/{id}/status": {
+            "patch": {
+                "description": "Moves the order to another lifecycle status: created -\u003e paid -\u003e assembled -\u003e shipped -\u003e delivered,\ncancelled before shipping, returned after shipping. Repeating the current status is a no-op.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "order"
+                ],
+                "summary": "Update order status",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "order uid",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "new status",
+                        "name": "update",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/rest.StatusUpdateReq"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Order"
+                        }
+                    },
+                    "400": {
+                        "description": "malformed body or unknown status",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResp"
+                        }
+                    },
+                    "404": {
+                        "description": "order not found",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResp"
+                        }
+                    },
+                    "409": {
+                        "description": "transition not allowed or status changed concurrently",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResp"
+                        }
+                    },
+                    "422": {
+                        "description": "validation failed",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ValidationErrorResp"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResp"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/orders": {
+            "get": {
+                "description": "Returns orders matching the filters, newest first. Use next_cursor from the response to fetch the next page.",
+                "tags": [
+                    "order"
+                ],
+                "summary": "List orders",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "customer id",
+                        "name": "customer_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "track number",
+                        "name": "track_number",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "delivery service",
+                        "name": "delivery_service",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "created at or after (RFC 3339)",
+                        "name": "created_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "created at or before (RFC 3339)",
+                        "name": "created_to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "payment currency",
+                        "name": "currency",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "payment provider",
+                        "name": "provider",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "brand of at least one item",
+                        "name": "brand",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "page size (1-100, default 20)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "next_cursor of the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.OrderPage"
+                        }
+                    },
+                    "400": {
+                        "description": "invalid filter",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResp"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResp"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Stores a new order or replaces the stored one if the version is higher. Delivery, payment and items\nare replaced, status and refunds are kept. Re-sending the stored content is a no-op.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "order"
+                ],
+                "summary": "Create or update order",
+                "parameters": [
+                    {
+                        "description": "order",
+                        "name": "order",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.Order"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "order updated or unchanged",
+                        "schema": {
+                            "$ref": "#/definitions/rest.UpsertResp"
+                        }
+                    },
+                    "201": {
+                        "description": "order created",
+                        "schema": {
+                            "$ref": "#/definitions/rest.UpsertResp"
+                        }
+                    },
+                    "400": {
+                        "description": "malformed body",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResp"
+                        }
+                    },
+                    "409": {
+                        "description": "a newer version of the order is stored or the new one conflicts with its refunds",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResp"
+                        }
+                    },
+                    "422": {
+                        "description": "validation failed",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ValidationErrorResp"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResp"
+                        }
+                    },
+                    "503": {
+                        "description": "temporary failure, retry the request",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResp"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Validates and stores a single order, the same way orders from Kafka are stored",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "order"
+                ],
+                "summary": "Create order",
+                "parameters": [
+                    {
+                        "description": "order",
+                        "name": "order",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.Order"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/rest.CreatedResp"
+                        }
+                    },
+                    "400": {
+                        "description": "malformed body",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResp"
+                        }
+                    },
+                    "409": {
+                        "description": "order already exists",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResp"
+                        }
+                    },
+                    "422": {
+                        "description": "validation failed",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ValidationErrorResp"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResp"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/orders/batch": {
+            "post": {
+                "description": "Validates and stores an array of orders and reports the outcome of each one.\nResponds with 201 when every order was stored and 207 otherwise.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "order"
+                ],
+                "summary": "Create orders in batch",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "order uid",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "orders",
+                        "name": "orders",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/dto.Order"
+                            }
+                        }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/dto.Order"
+                            "$ref": "#/definitions/rest.BatchResp"
                         }
                     },
-                    "404": {
-                        "description": "order not found",
+                    "207": {
+                        "description": "Multi-Status",
+                        "schema": {
+                            "$ref": "#/definitions/rest.BatchResp"
+                        }
+                    },
+                    "400": {
+                        "description": "malformed body or batch size out of range",
                         "schema": {
                             "$ref": "#/definitions/rest.ErrorResp"
                         }
@@ -52,9 +815,81 @@ const docTemplate = `{
                     }
                 }
             }
+        },
+        "/healthz": {
+            "get": {
+                "description": "Reports that the process is up",
+                "tags": [
+                    "health"
+                ],
+                "summary": "Liveness probe",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/health.Report"
+                        }
+                    }
+                }
+            }
+        },
+        "/readyz": {
+            "get": {
+                "description": "Checks Postgres, Kafka and cache warm-up and reports the state of each dependency",
+                "tags": [
+                    "health"
+                ],
+                "summary": "Readiness probe",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/health.Report"
+                        }
+                    },
+                    "503": {
+                        "description": "some dependency is unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/health.Report"
+                        }
+                    }
+                }
+            }
         }
     },
     "definitions": {
+        "dto.CacheStats": {
+            "type": "object",
+            "properties": {
+                "evictions": {
+                    "type": "integer"
+                },
+                "hit_ratio": {
+                    "type": "number"
+                },
+                "hits": {
+                    "type": "integer"
+                },
+                "load_failures": {
+                    "type": "integer"
+                },
+                "load_successes": {
+                    "type": "integer"
+                },
+                "misses": {
+                    "type": "integer"
+                },
+                "missing_size": {
+                    "type": "integer"
+                },
+                "saved_queries": {
+                    "type": "integer"
+                },
+                "size": {
+                    "type": "integer"
+                }
+            }
+        },
         "dto.Delivery": {
             "type": "object",
             "required": [
@@ -186,6 +1021,9 @@ const docTemplate = `{
                 "locale": {
                     "type": "string"
                 },
+                "net_amount": {
+                    "type": "integer"
+                },
                 "oof_shard": {
                     "type": "string"
                 },
@@ -195,14 +1033,47 @@ const docTemplate = `{
                 "payment": {
                     "$ref": "#/definitions/dto.Payment"
                 },
+                "refunded_amount": {
+                    "type": "integer"
+                },
+                "refunds": {
+                    "description": "Refunds, RefundedAmount and NetAmount are maintained by the service.\nNetAmount is payment.amount less RefundedAmount.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.Refund"
+                    }
+                },
                 "shardkey": {
                     "type": "string"
                 },
                 "sm_id": {
                     "type": "integer"
                 },
+                "status": {
+                    "description": "Status is assigned by the service: new orders always start as created.",
+                    "type": "string"
+                },
                 "track_number": {
                     "type": "string"
+                },
+                "version": {
+                    "description": "Version orders revisions of the same order: a higher version replaces\nthe stored one on upsert, a lower one is ignored.",
+                    "type": "integer",
+                    "minimum": 0
+                }
+            }
+        },
+        "dto.OrderPage": {
+            "type": "object",
+            "properties": {
+                "next_cursor": {
+                    "type": "string"
+                },
+                "orders": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.Order"
+                    }
                 }
             }
         },
@@ -252,6 +1123,123 @@ const docTemplate = `{
                 }
             }
         },
+        "dto.Refund": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "integer"
+                },
+                "chrt_id": {
+                    "description": "ChrtID is set for item refunds and empty for whole order refunds.",
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "reason": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.StatusChange": {
+            "type": "object",
+            "properties": {
+                "changed_at": {
+                    "type": "string"
+                },
+                "from": {
+                    "type": "string"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "to": {
+                    "type": "string"
+                }
+            }
+        },
+        "health.CheckResult": {
+            "type": "object",
+            "properties": {
+                "duration_ms": {
+                    "type": "integer"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "health.Report": {
+            "type": "object",
+            "properties": {
+                "checks": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/health.CheckResult"
+                    }
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "rest.BatchItemResp": {
+            "type": "object",
+            "properties": {
+                "errors": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/validator.FieldError"
+                    }
+                },
+                "message": {
+                    "type": "string"
+                },
+                "order_uid": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "integer"
+                }
+            }
+        },
+        "rest.BatchResp": {
+            "type": "object",
+            "properties": {
+                "created": {
+                    "type": "integer"
+                },
+                "failed": {
+                    "type": "integer"
+                },
+                "results": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/rest.BatchItemResp"
+                    }
+                }
+            }
+        },
+        "rest.CancelReq": {
+            "type": "object",
+            "properties": {
+                "reason": {
+                    "type": "string",
+                    "example": "customer request"
+                }
+            }
+        },
+        "rest.CreatedResp": {
+            "type": "object",
+            "properties": {
+                "order_uid": {
+                    "type": "string"
+                }
+            }
+        },
         "rest.ErrorResp": {
             "type": "object",
             "properties": {
@@ -262,6 +1250,79 @@ const docTemplate = `{
                     "type": "string"
                 }
             }
+        },
+        "rest.RefundReq": {
+            "type": "object",
+            "properties": {
+                "chrt_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    },
+                    "example": [
+                        9934930
+                    ]
+                },
+                "reason": {
+                    "type": "string",
+                    "example": "damaged in delivery"
+                }
+            }
+        },
+        "rest.StatusUpdateReq": {
+            "type": "object",
+            "properties": {
+                "reason": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "paid"
+                }
+            }
+        },
+        "rest.UpsertResp": {
+            "type": "object",
+            "properties": {
+                "order_uid": {
+                    "type": "string"
+                },
+                "outcome": {
+                    "type": "string",
+                    "example": "updated"
+                }
+            }
+        },
+        "rest.ValidationErrorResp": {
+            "type": "object",
+            "properties": {
+                "errors": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/validator.FieldError"
+                    }
+                },
+                "message": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "validator.FieldError": {
+            "type": "object",
+            "properties": {
+                "field": {
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "rule": {
+                    "type": "string"
+                }
+            }
         }
     }
 }`
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_items_brand;
+DROP INDEX IF EXISTS idx_items_order_id;
+DROP INDEX IF EXISTS idx_orders_date_created_id;
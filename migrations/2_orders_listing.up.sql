@@ -0,0 +1,3 @@
+CREATE INDEX IF NOT EXISTS idx_orders_date_created_id ON orders (date_created DESC, id DESC);
+CREATE INDEX IF NOT EXISTS idx_items_order_id ON items (order_id);
+CREATE INDEX IF NOT EXISTS idx_items_brand ON items (brand);
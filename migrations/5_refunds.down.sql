@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_refunds_order_item;
+DROP INDEX IF EXISTS idx_refunds_order_id;
+DROP TABLE IF EXISTS refunds;
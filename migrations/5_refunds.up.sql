@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS refunds (
+    id BIGSERIAL PRIMARY KEY,
+    order_id UUID NOT NULL REFERENCES orders(id) ON DELETE CASCADE,
+    chrt_id BIGINT REFERENCES items(chrt_id) ON DELETE CASCADE,
+    amount INT NOT NULL CHECK (amount > 0),
+    reason TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_refunds_order_id ON refunds (order_id, created_at, id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_refunds_order_item ON refunds (order_id, chrt_id) WHERE chrt_id IS NOT NULL;
@@ -0,0 +1,12 @@
+-- refunds of items dropped by a newer version would break the restored constraint, the old schema
+-- would have cascaded them away together with their items
+DELETE FROM refunds
+WHERE chrt_id IS NOT NULL
+  AND NOT EXISTS (SELECT 1 FROM items WHERE items.chrt_id = refunds.chrt_id);
+
+ALTER TABLE refunds
+    ADD CONSTRAINT refunds_chrt_id_fkey FOREIGN KEY (chrt_id) REFERENCES items(chrt_id) ON DELETE CASCADE;
+
+ALTER TABLE orders
+    DROP COLUMN IF EXISTS content_hash,
+    DROP COLUMN IF EXISTS version;
@@ -0,0 +1,6 @@
+ALTER TABLE orders
+    ADD COLUMN IF NOT EXISTS version BIGINT NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS content_hash VARCHAR(64) NOT NULL DEFAULT '';
+
+-- items are replaced when a newer version of the order arrives, refunds must survive that
+ALTER TABLE refunds DROP CONSTRAINT IF EXISTS refunds_chrt_id_fkey;